@@ -9,9 +9,7 @@ import (
 
 const cIcaoStandardTemperatureR float64 = 518.67
 const cIcaoFreezingPointTemperatureR float64 = 459.67
-const cTemperatureGradient float64 = -3.56616e-03
 const cIcaoStandardHumidity float64 = 0.0
-const cPressureExponent float64 = -5.255876
 const cSpeedOfSound float64 = 49.0223
 const cA0 float64 = 1.24871
 const cA1 float64 = 0.0988438
@@ -23,15 +21,147 @@ const cStandardTemperature float64 = 59.0
 const cStandardPressure float64 = 29.92
 const cStandardDensity float64 = 0.076474
 
+//the speed of sound at the sea level of the ICAO standard atmosphere in feet per second,
+//i.e. cSpeedOfSound * sqrt(cIcaoStandardTemperatureR)
+const cStandardSpeedOfSound float64 = 1116.45
+
+const cFeetPerMeter float64 = 3.2808399
+
+//Magnus formula coefficients (Alduchov and Eskridge, 1996)
+const cMagnusPressure float64 = 6.1094
+const cMagnusA float64 = 17.625
+const cMagnusB float64 = 243.04
+
+//the psychrometer coefficients for the ventilated psychrometer (per Celsius degree)
+const cPsychrometerCoefficient float64 = 6.53e-4
+const cPsychrometerTemperatureCoefficient float64 = 0.000944
+
+//DensityModelPolynomial is the identifier of the default air density model which uses the empirical
+//polynomial to calculate the pressure of the water vapor
+const DensityModelPolynomial byte = 1
+
+//DensityModelCIPM2007 is the identifier of the air density model defined by the International Committee
+//for Weights and Measures (CIPM-2007) which accounts for the enhancement factor and the compressibility of the moist air
+const DensityModelCIPM2007 byte = 2
+
+//CIPM-2007 constants, the pressure is in Pa and the temperature is in Kelvin
+const cCipmGasConstant float64 = 8.314472
+const cCipmDryAirMolarMass float64 = 28.96546e-3
+const cCipmWaterMolarMass float64 = 18.01528e-3
+const cCipmKilogramsPerCubicMeterPerPoundsPerCubicFoot float64 = 16.01846337
+
+var cipmSaturation = []float64{1.2378847e-5, -1.9121316e-2, 33.93711047, -6.3431645e3}
+var cipmEnhancement = []float64{1.00062, 3.14e-8, 5.6e-7}
+var cipmCompressibility = []float64{1.58123e-6, -2.9331e-8, 1.1043e-10, 5.707e-6, -2.051e-8, 1.9898e-4, -2.376e-6, 1.83e-11, -0.765e-8}
+
+//the range and the accuracy of the density altitude calculation in feet
+const cMinimumDensityAltitude float64 = -20000
+const cMaximumDensityAltitude float64 = 280000
+const cDensityAltitudeAccuracy float64 = 0.01
+
+//the constants of the ICAO standard atmosphere in SI units
+const cIsaGravity float64 = 9.80665
+const cIsaGasConstant float64 = 287.05287
+const cIsaEarthRadius float64 = 6356766
+
+//isaLayer describes one layer of the ICAO standard atmosphere
+//
+//The altitude is the geopotential altitude of the layer base in meters, the temperature is
+//the temperature at the base in Kelvin and the lapse rate is the temperature gradient in Kelvin
+//per meter. The pressure at the base is expressed as the ratio to the sea level pressure.
+type isaLayer struct {
+	altitude    float64
+	temperature float64
+	lapseRate   float64
+	pressure    float64
+}
+
+//isaLayers are the layers of the ICAO standard atmosphere up to 84852 meters of geopotential altitude
+//(about 86 kilometers of geometric altitude)
+var isaLayers = createIsaLayers([]isaLayer{
+	{altitude: 0, temperature: 288.15, lapseRate: -0.0065},   //troposphere
+	{altitude: 11000, temperature: 216.65, lapseRate: 0},     //tropopause
+	{altitude: 20000, temperature: 216.65, lapseRate: 0.001}, //stratosphere
+	{altitude: 32000, temperature: 228.65, lapseRate: 0.0028},
+	{altitude: 47000, temperature: 270.65, lapseRate: 0},       //stratopause
+	{altitude: 51000, temperature: 270.65, lapseRate: -0.0028}, //mesosphere
+	{altitude: 71000, temperature: 214.65, lapseRate: -0.002},
+	{altitude: 84852, temperature: 186.946, lapseRate: 0}, //mesopause
+})
+
+//createIsaLayers calculates the pressure at the base of each layer
+func createIsaLayers(layers []isaLayer) []isaLayer {
+	layers[0].pressure = 1
+	for i := 1; i < len(layers); i++ {
+		var t, p = layers[i-1].conditions(layers[i].altitude)
+		layers[i].temperature = t
+		layers[i].pressure = p
+	}
+	return layers
+}
+
+//conditions returns the temperature (in Kelvin) and the pressure ratio at the geopotential
+//altitude (in meters) within the layer
+func (v isaLayer) conditions(altitude float64) (float64, float64) {
+	var height = altitude - v.altitude
+	if v.lapseRate == 0 {
+		return v.temperature, v.pressure * math.Exp(-cIsaGravity*height/(cIsaGasConstant*v.temperature))
+	}
+	var temperature = v.temperature + v.lapseRate*height
+	return temperature, v.pressure * math.Pow(v.temperature/temperature, cIsaGravity/(cIsaGasConstant*v.lapseRate))
+}
+
+//isaConditions returns the temperature (in Rankine degrees) and the pressure (in inHg) of the ICAO
+//standard atmosphere at the geometric altitude specified (in feet)
+//
+//The altitudes above the highest layer are calculated as the part of that layer.
+func isaConditions(altitude float64) (float64, float64) {
+	var meters = altitude / cFeetPerMeter
+	var geopotential = cIsaEarthRadius * meters / (cIsaEarthRadius + meters)
+
+	var layer = isaLayers[0]
+	for _, next := range isaLayers[1:] {
+		if geopotential < next.altitude {
+			break
+		}
+		layer = next
+	}
+	var temperature, pressure = layer.conditions(geopotential)
+	return temperature * 1.8, pressure * cStandardPressure
+}
+
+//isaDensityRatio returns the ratio of the ICAO standard atmosphere density at the geometric altitude
+//specified (in feet) to the standard density at the sea level
+func isaDensityRatio(altitude float64) float64 {
+	var t, p = isaConditions(altitude)
+	return p / cStandardPressure * cIcaoStandardTemperatureR / t
+}
+
+//isaAltitudeForDensityRatio returns the geometric altitude (in feet) at which the density of the ICAO
+//standard atmosphere has the ratio to the standard density at the sea level specified
+func isaAltitudeForDensityRatio(ratio float64) float64 {
+	var low, high = cMinimumDensityAltitude, cMaximumDensityAltitude
+	for i := 0; i < 100 && high-low > cDensityAltitudeAccuracy; i++ {
+		var middle = (low + high) / 2
+		if isaDensityRatio(middle) > ratio {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return (low + high) / 2
+}
+
 //Atmosphere describes the atmosphere conditions
 type Atmosphere struct {
-	altitude    unit.Distance
-	pressure    unit.Pressure
-	temperature unit.Temperature
-	humidity    float64
-	density     float64
-	mach        unit.Velocity
-	mach1       float64
+	altitude     unit.Distance
+	pressure     unit.Pressure
+	temperature  unit.Temperature
+	humidity     float64
+	density      float64
+	mach         unit.Velocity
+	mach1        float64
+	densityModel byte
 }
 
 //CreateDefaultAtmosphere creates a default atmosphere used in ballistic calculations
@@ -45,36 +175,89 @@ func CreateDefaultAtmosphere() Atmosphere {
 }
 
 //CreateAtmosphere creates the atmosphere with the specified parameter
+//
+//The pressure is the station pressure at the altitude specified. Use CreateAtmosphereWithBarometricPressure
+//if the pressure is corrected to the sea level.
 func CreateAtmosphere(altitude unit.Distance, pressure unit.Pressure, temperature unit.Temperature, humidity float64) (Atmosphere, error) {
 	if humidity < 0 || humidity > 100 {
-		return CreateDefaultAtmosphere(), fmt.Errorf("Atmosphere : humidity must be in 0..1 or 0..100 range")
+		return CreateDefaultAtmosphere(), fmt.Errorf("Atmosphere : %w", ErrInvalidHumidity)
 	}
 
 	if humidity > 1 {
 		humidity = humidity / 100
 	}
 
+	return createAtmosphere(altitude, pressure, temperature, humidity), nil
+}
+
+//CreateAtmosphereWithRelativeHumidity creates the atmosphere with the specified parameters
+//
+//Unlike CreateAtmosphere, the humidity is always the 0 to 1 coefficient, so 1 means 100%
+//and the values above 1 are rejected.
+func CreateAtmosphereWithRelativeHumidity(altitude unit.Distance, pressure unit.Pressure, temperature unit.Temperature, humidity float64) (Atmosphere, error) {
+	if !(humidity >= 0 && humidity <= 1) {
+		return CreateDefaultAtmosphere(), fmt.Errorf("Atmosphere : %w, the humidity %g must be in 0..1 range", ErrInvalidHumidity, humidity)
+	}
+	return createAtmosphere(altitude, pressure, temperature, humidity), nil
+}
+
+//CreateAtmosphereWithDewPoint creates the atmosphere with the relative humidity calculated from the dew point
+//
+//The dew point must not be above the temperature.
+func CreateAtmosphereWithDewPoint(altitude unit.Distance, pressure unit.Pressure, temperature unit.Temperature, dewPoint unit.Temperature) (Atmosphere, error) {
+	var t = temperature.In(unit.TemperatureCelsius)
+	var td = dewPoint.In(unit.TemperatureCelsius)
+	if td > t {
+		return CreateDefaultAtmosphere(), fmt.Errorf("Atmosphere : %w, the dew point %s is above the temperature %s", ErrInvalidHumidity, dewPoint, temperature)
+	}
+	return createAtmosphere(altitude, pressure, temperature, saturationVaporPressure(td)/saturationVaporPressure(t)), nil
+}
+
+//CreateAtmosphereWithWetBulb creates the atmosphere with the relative humidity calculated from the temperature
+//of the wet-bulb thermometer of the psychrometer
+//
+//The wet-bulb temperature must not be above the temperature.
+func CreateAtmosphereWithWetBulb(altitude unit.Distance, pressure unit.Pressure, temperature unit.Temperature, wetBulb unit.Temperature) (Atmosphere, error) {
+	var t = temperature.In(unit.TemperatureCelsius)
+	var tw = wetBulb.In(unit.TemperatureCelsius)
+	if tw > t {
+		return CreateDefaultAtmosphere(), fmt.Errorf("Atmosphere : %w, the wet-bulb temperature %s is above the temperature %s", ErrInvalidHumidity, wetBulb, temperature)
+	}
+
+	//the psychrometric equation for the ventilated psychrometer, the pressures are in hPa
+	var p = pressure.In(unit.PressureHP)
+	var vaporPressure = saturationVaporPressure(tw) - cPsychrometerCoefficient*(1+cPsychrometerTemperatureCoefficient*tw)*p*(t-tw)
+	if vaporPressure < 0 {
+		return CreateDefaultAtmosphere(), fmt.Errorf("Atmosphere : %w, the wet-bulb temperature %s is too low for the temperature %s", ErrInvalidHumidity, wetBulb, temperature)
+	}
+	return createAtmosphere(altitude, pressure, temperature, vaporPressure/saturationVaporPressure(t)), nil
+}
+
+func createAtmosphere(altitude unit.Distance, pressure unit.Pressure, temperature unit.Temperature, humidity float64) Atmosphere {
 	a := Atmosphere{altitude: altitude,
 		pressure:    pressure,
 		temperature: temperature,
 		humidity:    humidity}
 
 	a.calculate()
-	return a, nil
+	return a
+}
+
+//saturationVaporPressure returns the saturation pressure of water vapor (in hPa) at the temperature specified (in Celsius)
+//
+//The pressure is calculated using Magnus formula with Alduchov and Eskridge coefficients.
+func saturationVaporPressure(temperature float64) float64 {
+	return cMagnusPressure * math.Exp(cMagnusA*temperature/(temperature+cMagnusB))
 }
 
 //CreateICAOAtmosphere creates default ICAO atmosphere for the specified altitude
+//
+//The temperature and the pressure are calculated using the layers of the ICAO standard atmosphere
+//(troposphere, tropopause, stratosphere and mesosphere) up to about 86 kilometers.
 func CreateICAOAtmosphere(altitude unit.Distance) Atmosphere {
-	temperature := unit.MustCreateTemperature(
-		cIcaoStandardTemperatureR+
-			altitude.In(unit.DistanceFoot)*cTemperatureGradient-cIcaoFreezingPointTemperatureR,
-		unit.TemperatureFahrenheit)
-
-	pressure := unit.MustCreatePressure(
-		cStandardPressure*
-			math.Pow(cIcaoStandardTemperatureR/(temperature.In(unit.TemperatureFahrenheit)+
-				cIcaoFreezingPointTemperatureR),
-				cPressureExponent), unit.PressureInHg)
+	var t, p = isaConditions(altitude.In(unit.DistanceFoot))
+	temperature := unit.MustCreateTemperature(t-cIcaoFreezingPointTemperatureR, unit.TemperatureFahrenheit)
+	pressure := unit.MustCreatePressure(p, unit.PressureInHg)
 
 	a := Atmosphere{
 		altitude:    altitude,
@@ -87,6 +270,62 @@ func CreateICAOAtmosphere(altitude unit.Distance) Atmosphere {
 	return a
 }
 
+//CreateAtmosphereWithBarometricPressure creates the atmosphere using the barometric pressure corrected
+//to the sea level (e.g. the altimeter setting or QNH of the weather reports) instead of the station pressure
+//
+//The station pressure at the altitude is calculated using the ICAO standard atmosphere
+//(see StationPressureFromBarometric).
+func CreateAtmosphereWithBarometricPressure(altitude unit.Distance, barometricPressure unit.Pressure, temperature unit.Temperature, humidity float64) (Atmosphere, error) {
+	return CreateAtmosphere(altitude, StationPressureFromBarometric(barometricPressure, altitude), temperature, humidity)
+}
+
+//StationPressureFromBarometric converts the barometric pressure corrected to the sea level into the pressure
+//at the altitude specified using the ICAO standard atmosphere
+func StationPressureFromBarometric(barometricPressure unit.Pressure, altitude unit.Distance) unit.Pressure {
+	var _, p = isaConditions(altitude.In(unit.DistanceFoot))
+	return unit.MustCreatePressure(barometricPressure.In(unit.PressureInHg)*p/cStandardPressure, unit.PressureInHg).Convert(barometricPressure.Units())
+}
+
+//BarometricPressureFromStation converts the pressure at the altitude specified into the barometric pressure
+//corrected to the sea level using the ICAO standard atmosphere
+func BarometricPressureFromStation(stationPressure unit.Pressure, altitude unit.Distance) unit.Pressure {
+	var _, p = isaConditions(altitude.In(unit.DistanceFoot))
+	return unit.MustCreatePressure(stationPressure.In(unit.PressureInHg)*cStandardPressure/p, unit.PressureInHg).Convert(stationPressure.Units())
+}
+
+//CreateAtmosphereByDensityAltitude creates the atmosphere at the altitude specified which has
+//the same air density as the ICAO standard atmosphere at the density altitude specified
+//
+//The temperature is set to the standard temperature at the altitude and the pressure is calculated
+//to match the density. The humidity is set to zero because the density altitude already includes its effect.
+func CreateAtmosphereByDensityAltitude(altitude unit.Distance, densityAltitude unit.Distance) Atmosphere {
+	var t, _ = isaConditions(altitude.In(unit.DistanceFoot))
+	var a, _ = CreateAtmosphereByDensityAltitudeAndTemperature(altitude, densityAltitude,
+		unit.MustCreateTemperature(t-cIcaoFreezingPointTemperatureR, unit.TemperatureFahrenheit))
+	return a
+}
+
+//CreateAtmosphereByDensityAltitudeAndTemperature creates the atmosphere at the altitude and the temperature
+//specified which has the same air density as the ICAO standard atmosphere at the density altitude specified
+//
+//The pressure is calculated to match the density. The humidity is set to zero because the density altitude
+//already includes its effect.
+func CreateAtmosphereByDensityAltitudeAndTemperature(altitude unit.Distance, densityAltitude unit.Distance, temperature unit.Temperature) (Atmosphere, error) {
+	var t = temperature.In(unit.TemperatureFahrenheit) + cIcaoFreezingPointTemperatureR
+	if !isPositive(t) {
+		return CreateDefaultAtmosphere(), fmt.Errorf("Atmosphere : %w, the temperature must be above absolute zero", ErrInvalidParameter)
+	}
+	var ratio = isaDensityRatio(densityAltitude.In(unit.DistanceFoot))
+
+	a := Atmosphere{altitude: altitude,
+		pressure:    unit.MustCreatePressure(ratio*cStandardPressure*t/cIcaoStandardTemperatureR, unit.PressureInHg),
+		temperature: temperature,
+		humidity:    0}
+
+	a.calculate()
+	return a, nil
+}
+
 //Altitude returns the ground level altitude over the sea level
 func (a Atmosphere) Altitude() unit.Distance {
 	return a.altitude
@@ -97,11 +336,18 @@ func (a Atmosphere) Temperature() unit.Temperature {
 	return a.temperature
 }
 
-//Pressure returns the pressure at the ground level
+//Pressure returns the pressure at the ground level (the station pressure)
 func (a Atmosphere) Pressure() unit.Pressure {
 	return a.pressure
 }
 
+//BarometricPressure returns the pressure corrected to the sea level
+//
+//The pressure is calculated from the station pressure (see Pressure) using the ICAO standard atmosphere.
+func (a Atmosphere) BarometricPressure() unit.Pressure {
+	return BarometricPressureFromStation(a.pressure, a.altitude)
+}
+
 //Humidity returns the relative humidity set in 0 to 1 coefficient
 //
 //multiply this value by 100 to get percents
@@ -109,6 +355,19 @@ func (a Atmosphere) Humidity() float64 {
 	return a.humidity
 }
 
+//DewPoint returns the temperature at which the water vapor in the air starts to condense
+//
+//The dew point of the dry air (zero humidity) is the absolute zero.
+func (a Atmosphere) DewPoint() unit.Temperature {
+	var units = a.temperature.Units()
+	if a.humidity <= 0 {
+		return unit.MustCreateTemperature(0, unit.TemperatureKelvin).Convert(units)
+	}
+	var t = a.temperature.In(unit.TemperatureCelsius)
+	var gamma = math.Log(a.humidity) + cMagnusA*t/(cMagnusB+t)
+	return unit.MustCreateTemperature(cMagnusB*gamma/(cMagnusA-gamma), unit.TemperatureCelsius).Convert(units)
+}
+
 //HumidityInPercents returns relative humidity in percents (0..100)
 func (a Atmosphere) HumidityInPercents() float64 {
 	return a.humidity * 100
@@ -119,6 +378,31 @@ func (a Atmosphere) String() string {
 		a.altitude, a.pressure, a.temperature, a.humidity*100)
 }
 
+//DensityModel returns the model used to calculate the air density (see DensityModel* constants)
+func (a Atmosphere) DensityModel() byte {
+	if a.densityModel == 0 {
+		return DensityModelPolynomial
+	}
+	return a.densityModel
+}
+
+//SetDensityModel sets the model used to calculate the air density (see DensityModel* constants)
+//
+//DensityModelPolynomial is used by default.
+func (a *Atmosphere) SetDensityModel(model byte) error {
+	if model != DensityModelPolynomial && model != DensityModelCIPM2007 {
+		return fmt.Errorf("Atmosphere : %w, the density model %d is unknown", ErrInvalidParameter, model)
+	}
+	a.densityModel = model
+	a.calculate()
+	return nil
+}
+
+//Density returns the air density in pounds per cubic foot
+func (a Atmosphere) Density() float64 {
+	return a.density
+}
+
 func (a Atmosphere) getDensity() float64 {
 	return a.density
 }
@@ -127,6 +411,12 @@ func (a Atmosphere) getDensityFactor() float64 {
 	return a.density / cStandardDensity
 }
 
+//DensityAltitude returns the altitude at which the ICAO standard atmosphere has the same air density as this atmosphere
+func (a Atmosphere) DensityAltitude() unit.Distance {
+	var altitude = isaAltitudeForDensityRatio(a.density / cStandardDensity)
+	return unit.MustCreateDistance(altitude, unit.DistanceFoot).Convert(a.altitude.Units())
+}
+
 //Mach returns the speed of sound at the atmosphere with such parameters
 func (a Atmosphere) Mach() unit.Velocity {
 	return a.mach
@@ -135,12 +425,19 @@ func (a Atmosphere) Mach() unit.Velocity {
 func (a *Atmosphere) calculate0(t, p float64) (float64, float64) {
 	var hc, et, et0, density, mach float64
 
+	if a.densityModel == DensityModelCIPM2007 {
+		mach = math.Sqrt(t+cIcaoFreezingPointTemperatureR) * cSpeedOfSound
+		return calculateDensityCIPM2007(t, p, a.humidity), mach
+	}
+
 	if t > 0.0 {
 		et0 = cA0 + t*(cA1+t*(cA2+t*(cA3+t*cA4)))
 		et = cA5 * a.humidity * et0
 		hc = (p - 0.3783*et) / cStandardPressure
 	} else {
-		hc = 1.0
+		//the polynomial of the water vapor pressure is for the temperatures above 0°F only,
+		//the vapor pressure is neglected in the colder air, but the air pressure is not
+		hc = p / cStandardPressure
 	}
 	density = cStandardDensity * (cIcaoStandardTemperatureR / (t + cIcaoFreezingPointTemperatureR)) * hc
 	mach = math.Sqrt(t+cIcaoFreezingPointTemperatureR) * cSpeedOfSound
@@ -159,8 +456,13 @@ func (a *Atmosphere) calculate() {
 	a.mach = unit.MustCreateVelocity(mach, unit.VelocityFPS)
 }
 
+//getDensityFactorAndMachForAltitude returns the density factor and the speed of sound at the altitude specified
+//
+//The difference between the actual and the standard temperature and the ratio between the actual and the standard
+//pressure at the altitude of the atmosphere are kept at other altitudes, the standard values change as the layers
+//of the ICAO standard atmosphere define.
 func (a *Atmosphere) getDensityFactorAndMachForAltitude(altitude float64) (float64, float64) {
-	var t, t0, p, ta, tb, orgAltitude, density, mach float64
+	var t, t0, p, ta, tb, pa, pb, orgAltitude, density, mach float64
 
 	orgAltitude = a.altitude.In(unit.DistanceFoot)
 
@@ -173,11 +475,30 @@ func (a *Atmosphere) getDensityFactorAndMachForAltitude(altitude float64) (float
 	t0 = a.temperature.In(unit.TemperatureFahrenheit)
 	p = a.pressure.In(unit.PressureInHg)
 
-	ta = cIcaoStandardTemperatureR + orgAltitude*cTemperatureGradient - cIcaoFreezingPointTemperatureR
-	tb = cIcaoStandardTemperatureR + altitude*cTemperatureGradient - cIcaoFreezingPointTemperatureR
-	t = t0 + ta - tb
-	p = p * math.Pow(t0/t, cPressureExponent)
+	ta, pa = isaConditions(orgAltitude)
+	tb, pb = isaConditions(altitude)
+	t = t0 + tb - ta
+	p = p * pb / pa
 
 	density, mach = a.calculate0(t, p)
 	return density / cStandardDensity, mach
 }
+
+//calculateDensityCIPM2007 calculates the density of the moist air (in pounds per cubic foot) at the temperature
+//(in Fahrenheit degrees), the pressure (in inHg) and the relative humidity (0 to 1) specified using CIPM-2007 equation
+func calculateDensityCIPM2007(t, p, humidity float64) float64 {
+	var celsius = (t - 32) / 1.8
+	var kelvin = celsius + 273.15
+	var pascals = unit.MustCreatePressure(p, unit.PressureInHg).In(unit.PressureHP) * 100
+
+	var saturation = math.Exp(cipmSaturation[0]*kelvin*kelvin + cipmSaturation[1]*kelvin + cipmSaturation[2] + cipmSaturation[3]/kelvin)
+	var enhancement = cipmEnhancement[0] + cipmEnhancement[1]*pascals + cipmEnhancement[2]*celsius*celsius
+	var vapor = humidity * enhancement * saturation / pascals
+
+	var c = cipmCompressibility
+	var compressibility = 1 - pascals/kelvin*(c[0]+c[1]*celsius+c[2]*celsius*celsius+(c[3]+c[4]*celsius)*vapor+(c[5]+c[6]*celsius)*vapor*vapor) +
+		pascals*pascals/(kelvin*kelvin)*(c[7]+c[8]*vapor*vapor)
+
+	var density = pascals * cCipmDryAirMolarMass / (compressibility * cCipmGasConstant * kelvin) * (1 - vapor*(1-cCipmWaterMolarMass/cCipmDryAirMolarMass))
+	return density / cCipmKilogramsPerCubicMeterPerPoundsPerCubicFoot
+}
@@ -3,6 +3,9 @@ package externalballistics
 import (
 	"fmt"
 	"math"
+	"sort"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
 )
 
 //DragTableG1 is identifier for G1 ballistic table
@@ -29,7 +32,9 @@ const DragTableGS byte = 7
 //DragTableGC for a custom drag table
 const DragTableGC byte = 8
 
-type dragFunction func(float64) float64
+//DragFunction is a function which returns the drag coefficient for the speed of the projectile
+//expressed in mach (speed of sound)
+type DragFunction func(float64) float64
 
 //
 //BallisticCoefficient keeps data about ballistic coefficient
@@ -53,7 +58,9 @@ type BallisticCoefficient struct {
 	// the identifier of the ballistic table
 	table byte
 	// the drag function
-	drag dragFunction
+	drag DragFunction
+	// the ballistic coefficient values for the velocity bands, ordered by velocity
+	multipleValues []BallisticCoefficientPoint
 }
 
 //
@@ -66,49 +73,49 @@ const BC = 1
 //
 const FF = 2
 
-func dragFunctionFactory(dragTable byte) dragFunction {
+func dragFunctionFactory(dragTable byte) (DragFunction, error) {
 	switch dragTable {
 	case DragTableG1:
 		return func(mach float64) float64 {
 			return CalculateByCurve(g1Table, g1Curve, mach)
-		}
+		}, nil
 	case DragTableG2:
 		return func(mach float64) float64 {
 			return CalculateByCurve(g2Table, g2Curve, mach)
-		}
+		}, nil
 	case DragTableG5:
 		return func(mach float64) float64 {
 			return CalculateByCurve(g5Table, g5Curve, mach)
-		}
+		}, nil
 	case DragTableG6:
 		return func(mach float64) float64 {
 			return CalculateByCurve(g6Table, g6Curve, mach)
-		}
+		}, nil
 	case DragTableG7:
 		return func(mach float64) float64 {
 			return CalculateByCurve(g7Table, g7Curve, mach)
-		}
+		}, nil
 	case DragTableG8:
 		return func(mach float64) float64 {
 			return CalculateByCurve(g8Table, g8Curve, mach)
-		}
+		}, nil
 	case DragTableGS:
 		return func(mach float64) float64 {
 			return CalculateByCurve(gSTable, gSCurve, mach)
-		}
+		}, nil
 	default:
-		panic(fmt.Errorf("unknown drag table type"))
+		return nil, fmt.Errorf("BallisticCoefficient: %w %d", ErrInvalidDragTable, dragTable)
 	}
 }
 
-//CreateBallisticCoefficient creates ballistic coefficient object using the
+//CreateBallisticCoefficientForCustomDragFunction creates ballistic coefficient object using the
 //the custom drag function
-func CreateBallisticCoefficientForCustomDragFunction(value float64, valueType byte, dragTable dragFunction) (BallisticCoefficient, error) {
+func CreateBallisticCoefficientForCustomDragFunction(value float64, valueType byte, dragTable DragFunction) (BallisticCoefficient, error) {
 	if value <= 0 {
-		return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: Drag coefficient must be greater than zero")
+		return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: %w, the drag coefficient must be greater than zero", ErrInvalidParameter)
 	}
 	if valueType != BC && valueType != FF {
-		return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: The value type must be either BC or FF")
+		return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: %w, the value type must be either BC or FF", ErrInvalidParameter)
 	}
 	return BallisticCoefficient{
 		value:     value,
@@ -118,20 +125,42 @@ func CreateBallisticCoefficientForCustomDragFunction(value float64, valueType by
 	}, nil
 }
 
+//CreateBallisticCoefficientForCustomDragTable creates ballistic coefficient object using the
+//custom drag table, e.g. the drag coefficients measured by Doppler radar or provided by
+//the projectile manufacturer.
+//
+//Each data point of the table must keep the speed expressed in mach as A and the drag
+//coefficient as B. The points must be ordered by the speed. The table can be loaded
+//from a file using LoadDragTableFile.
+func CreateBallisticCoefficientForCustomDragTable(value float64, valueType byte, dataPoints []DataPoint) (BallisticCoefficient, error) {
+	if err := validateDragTable(dataPoints); err != nil {
+		return BallisticCoefficient{}, err
+	}
+
+	var table = make([]DataPoint, len(dataPoints))
+	copy(table, dataPoints)
+	var curve = CalculateCurve(table)
+
+	return CreateBallisticCoefficientForCustomDragFunction(value, valueType, func(mach float64) float64 {
+		return CalculateByCurve(table, curve, mach)
+	})
+}
+
 //CreateBallisticCoefficient creates ballistic coefficient object using the
 //ballistic coefficient value and ballistic table.
 func CreateBallisticCoefficient(value float64, dragTable byte) (BallisticCoefficient, error) {
-	if dragTable < DragTableG1 || DragTableG1 > DragTableGS {
-		return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: Unknown drag table %d", dragTable)
+	drag, err := dragFunctionFactory(dragTable)
+	if err != nil {
+		return BallisticCoefficient{}, err
 	}
 	if value <= 0 {
-		return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: Drag coefficient must be greater than zero")
+		return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: %w, the drag coefficient must be greater than zero", ErrInvalidParameter)
 	}
 	return BallisticCoefficient{
 		value:     value,
 		valueType: BC,
 		table:     dragTable,
-		drag:      dragFunctionFactory(dragTable),
+		drag:      drag,
 	}, nil
 }
 
@@ -150,6 +179,107 @@ func (v BallisticCoefficient) Table() byte {
 	return v.table
 }
 
+//BallisticCoefficientPoint keeps the ballistic coefficient value
+//for one velocity of the projectile
+type BallisticCoefficientPoint struct {
+	value    float64
+	velocity unit.Velocity
+}
+
+//CreateBallisticCoefficientPoint creates ballistic coefficient value for the velocity specified
+func CreateBallisticCoefficientPoint(value float64, velocity unit.Velocity) BallisticCoefficientPoint {
+	return BallisticCoefficientPoint{
+		value:    value,
+		velocity: velocity,
+	}
+}
+
+//Value returns the ballistic coefficient value
+func (v BallisticCoefficientPoint) Value() float64 {
+	return v.value
+}
+
+//Velocity returns the velocity for which the ballistic coefficient value is set
+func (v BallisticCoefficientPoint) Velocity() unit.Velocity {
+	return v.velocity
+}
+
+//CreateMultipleBallisticCoefficient creates ballistic coefficient object using
+//the ballistic coefficient values published for different velocities of the projectile
+//and ballistic table.
+//
+//The ballistic coefficient is linearly interpolated between the velocities of the points
+//specified. The value of the closest point is used for the velocities beyond the points.
+func CreateMultipleBallisticCoefficient(dragTable byte, points ...BallisticCoefficientPoint) (BallisticCoefficient, error) {
+	if len(points) < 1 {
+		return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: %w, at least one ballistic coefficient value must be specified", ErrInvalidParameter)
+	}
+	var values = make([]BallisticCoefficientPoint, len(points))
+	copy(values, points)
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].velocity.In(unit.VelocityFPS) > values[j].velocity.In(unit.VelocityFPS)
+	})
+
+	bc, err := CreateBallisticCoefficient(values[0].value, dragTable)
+	if err != nil {
+		return BallisticCoefficient{}, err
+	}
+	for _, point := range values {
+		if point.value <= 0 {
+			return BallisticCoefficient{}, fmt.Errorf("BallisticCoefficient: %w, the drag coefficient must be greater than zero", ErrInvalidParameter)
+		}
+	}
+	bc.multipleValues = values
+	return bc, nil
+}
+
+//HasMultipleValues returns the flag indicating whether the ballistic coefficient
+//depends on the velocity of the projectile
+func (v BallisticCoefficient) HasMultipleValues() bool {
+	return len(v.multipleValues) > 0
+}
+
+//MultipleValues returns the ballistic coefficient values for the velocities of the projectile
+//ordered from the highest velocity to the lowest
+func (v BallisticCoefficient) MultipleValues() []BallisticCoefficientPoint {
+	var values = make([]BallisticCoefficientPoint, len(v.multipleValues))
+	copy(values, v.multipleValues)
+	return values
+}
+
+//scaled returns the ballistic coefficient with all values multiplied by the factor specified
+func (v BallisticCoefficient) scaled(factor float64) BallisticCoefficient {
+	var result = v
+	result.value = v.value * factor
+	if len(v.multipleValues) > 0 {
+		result.multipleValues = make([]BallisticCoefficientPoint, len(v.multipleValues))
+		for i, point := range v.multipleValues {
+			result.multipleValues[i] = BallisticCoefficientPoint{value: point.value * factor, velocity: point.velocity}
+		}
+	}
+	return result
+}
+
+func (v BallisticCoefficient) valueForVelocity(velocity float64) float64 {
+	var count = len(v.multipleValues)
+	if count == 0 {
+		return v.value
+	}
+	if velocity >= v.multipleValues[0].velocity.In(unit.VelocityFPS) {
+		return v.multipleValues[0].value
+	}
+	for i := 1; i < count; i++ {
+		var velocity1 = v.multipleValues[i].velocity.In(unit.VelocityFPS)
+		if velocity >= velocity1 {
+			var velocity0 = v.multipleValues[i-1].velocity.In(unit.VelocityFPS)
+			var value0 = v.multipleValues[i-1].value
+			var value1 = v.multipleValues[i].value
+			return value1 + (value0-value1)*(velocity-velocity1)/(velocity0-velocity1)
+		}
+	}
+	return v.multipleValues[count-1].value
+}
+
 //PIR = (PI/8)*(RHO0/144)
 const PIR = 2.08551e-04
 
@@ -162,6 +292,8 @@ func (v BallisticCoefficient) Drag(mach float64) float64 {
 //DataPoint is one value of the ballistic table used in
 //table-based calculations below
 //
+//A is the speed expressed in mach and B is the drag coefficient
+//
 //The calculation is based on original JavaScript code
 //by Alexandre Trofimov
 type DataPoint struct {
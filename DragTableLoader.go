@@ -0,0 +1,244 @@
+package externalballistics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const cDragTableColumnUnknown byte = 0
+const cDragTableColumnMach byte = 1
+const cDragTableColumnVelocityFPS byte = 2
+const cDragTableColumnVelocityMPS byte = 3
+const cDragTableColumnDrag byte = 4
+
+//dragTableColumn detects the kind of the data kept in the column by the column name
+//
+//The velocity is expected to be in feet per second unless the name contains
+//m/s or mps.
+func dragTableColumn(name string) byte {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case name == "":
+		return cDragTableColumnUnknown
+	case strings.HasPrefix(name, "mach") || name == "m" || name == "ma":
+		return cDragTableColumnMach
+	case strings.HasPrefix(name, "cd") || strings.HasPrefix(name, "c_d") || strings.HasPrefix(name, "drag"):
+		return cDragTableColumnDrag
+	case strings.HasPrefix(name, "velocity") || strings.HasPrefix(name, "speed") || name == "v" || strings.HasPrefix(name, "v ") || strings.HasPrefix(name, "v(") || strings.HasPrefix(name, "v_"):
+		if strings.Contains(name, "m/s") || strings.Contains(name, "mps") {
+			return cDragTableColumnVelocityMPS
+		}
+		return cDragTableColumnVelocityFPS
+	default:
+		return cDragTableColumnUnknown
+	}
+}
+
+//toMach converts the value of the speed column into mach
+func toMach(value float64, column byte) float64 {
+	switch column {
+	case cDragTableColumnVelocityFPS:
+		return value / cStandardSpeedOfSound
+	case cDragTableColumnVelocityMPS:
+		return value * cFeetPerMeter / cStandardSpeedOfSound
+	default:
+		return value
+	}
+}
+
+//validateDragTable checks that the drag table can be used to calculate the drag curve
+func validateDragTable(dataPoints []DataPoint) error {
+	if len(dataPoints) < 2 {
+		return fmt.Errorf("DragTable: %w: the drag table must have at least two points", ErrInvalidDragTable)
+	}
+	for i, point := range dataPoints {
+		if math.IsNaN(point.A) || math.IsNaN(point.B) || math.IsInf(point.A, 0) || math.IsInf(point.B, 0) {
+			return fmt.Errorf("DragTable: %w: the point %d is not a number", ErrInvalidDragTable, i+1)
+		}
+		if point.A < 0 || point.B < 0 {
+			return fmt.Errorf("DragTable: %w: the point %d must not be negative", ErrInvalidDragTable, i+1)
+		}
+		if i > 0 && point.A <= dataPoints[i-1].A {
+			return fmt.Errorf("DragTable: %w: the point %d must have greater speed than the previous one (%g <= %g)", ErrInvalidDragTable, i+1, point.A, dataPoints[i-1].A)
+		}
+	}
+	return nil
+}
+
+//LoadDragTableCSV reads the drag table from CSV data
+//
+//The first line may be a header with the column names. The speed column may be
+//named "mach" or "velocity" (feet per second unless the name contains "m/s" or "mps").
+//The velocity is converted to mach using the speed of sound at the sea level of the ICAO standard atmosphere.
+//The drag coefficient column may be named "cd" or "drag". If there is no header, the first
+//column is the speed expressed in mach and the second column is the drag coefficient.
+//
+//The points must be ordered by the speed. Lines started with # are ignored.
+func LoadDragTableCSV(reader io.Reader) ([]DataPoint, error) {
+	var csvReader = csv.NewReader(reader)
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	var records, err = csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("DragTable: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("DragTable: %w: the file is empty", ErrInvalidDragTable)
+	}
+
+	var speedColumn, dragColumn = 0, 1
+	var speedKind = cDragTableColumnMach
+	var firstRecord = 0
+
+	if !isNumericRecord(records[0]) {
+		speedColumn, dragColumn = -1, -1
+		for i, name := range records[0] {
+			switch kind := dragTableColumn(name); kind {
+			case cDragTableColumnMach, cDragTableColumnVelocityFPS, cDragTableColumnVelocityMPS:
+				if speedColumn < 0 {
+					speedColumn = i
+					speedKind = kind
+				}
+			case cDragTableColumnDrag:
+				if dragColumn < 0 {
+					dragColumn = i
+				}
+			}
+		}
+		if speedColumn < 0 {
+			return nil, fmt.Errorf("DragTable: %w: the header %q has no mach or velocity column", ErrInvalidDragTable, strings.Join(records[0], ","))
+		}
+		if dragColumn < 0 {
+			return nil, fmt.Errorf("DragTable: %w: the header %q has no drag coefficient column", ErrInvalidDragTable, strings.Join(records[0], ","))
+		}
+		firstRecord = 1
+	}
+
+	var dataPoints = make([]DataPoint, 0, len(records)-firstRecord)
+	for i, record := range records[firstRecord:] {
+		if speedColumn >= len(record) || dragColumn >= len(record) {
+			return nil, fmt.Errorf("DragTable: %w: the point %d has not enough columns", ErrInvalidDragTable, i+1)
+		}
+		speed, err := strconv.ParseFloat(strings.TrimSpace(record[speedColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("DragTable: %w: the speed %q of the point %d is not a number", ErrInvalidDragTable, record[speedColumn], i+1)
+		}
+		drag, err := strconv.ParseFloat(strings.TrimSpace(record[dragColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("DragTable: %w: the drag coefficient %q of the point %d is not a number", ErrInvalidDragTable, record[dragColumn], i+1)
+		}
+		dataPoints = append(dataPoints, DataPoint{A: toMach(speed, speedKind), B: drag})
+	}
+
+	if err = validateDragTable(dataPoints); err != nil {
+		return nil, err
+	}
+	return dataPoints, nil
+}
+
+func isNumericRecord(record []string) bool {
+	for _, field := range record {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(field), 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+//LoadDragTableJSON reads the drag table from JSON data
+//
+//The data must be an array of the points. Each point is either an array of two numbers
+//(the speed expressed in mach and the drag coefficient) or an object, e.g. {"mach": 0.5, "cd": 0.2}
+//or {"velocity": 1500, "cd": 0.3}. The names of the object fields are the same as the names of
+//the columns accepted by LoadDragTableCSV. The velocity is converted to mach using the speed of sound
+//at the sea level of the ICAO standard atmosphere.
+//
+//The points must be ordered by the speed.
+func LoadDragTableJSON(reader io.Reader) ([]DataPoint, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(reader).Decode(&items); err != nil {
+		return nil, fmt.Errorf("DragTable: %w: the data must be an array of points: %v", ErrInvalidDragTable, err)
+	}
+
+	var dataPoints = make([]DataPoint, 0, len(items))
+	for i, item := range items {
+		var pair []float64
+		if err := json.Unmarshal(item, &pair); err == nil {
+			if len(pair) != 2 {
+				return nil, fmt.Errorf("DragTable: %w: the point %d must have two values", ErrInvalidDragTable, i+1)
+			}
+			dataPoints = append(dataPoints, DataPoint{A: pair[0], B: pair[1]})
+			continue
+		}
+
+		var fields map[string]float64
+		if err := json.Unmarshal(item, &fields); err != nil {
+			return nil, fmt.Errorf("DragTable: %w: the point %d must be an array or an object of numbers", ErrInvalidDragTable, i+1)
+		}
+
+		var hasSpeed, hasDrag bool
+		var point DataPoint
+		for name, value := range fields {
+			switch kind := dragTableColumn(name); kind {
+			case cDragTableColumnMach, cDragTableColumnVelocityFPS, cDragTableColumnVelocityMPS:
+				if hasSpeed {
+					return nil, fmt.Errorf("DragTable: %w: the point %d has more than one speed value", ErrInvalidDragTable, i+1)
+				}
+				point.A = toMach(value, kind)
+				hasSpeed = true
+			case cDragTableColumnDrag:
+				if hasDrag {
+					return nil, fmt.Errorf("DragTable: %w: the point %d has more than one drag coefficient", ErrInvalidDragTable, i+1)
+				}
+				point.B = value
+				hasDrag = true
+			}
+		}
+		if !hasSpeed {
+			return nil, fmt.Errorf("DragTable: %w: the point %d has no mach or velocity value", ErrInvalidDragTable, i+1)
+		}
+		if !hasDrag {
+			return nil, fmt.Errorf("DragTable: %w: the point %d has no drag coefficient", ErrInvalidDragTable, i+1)
+		}
+		dataPoints = append(dataPoints, point)
+	}
+
+	if err := validateDragTable(dataPoints); err != nil {
+		return nil, err
+	}
+	return dataPoints, nil
+}
+
+//LoadDragTableFile reads the drag table from CSV or JSON file
+//
+//The format is detected by the file extension (.csv or .json).
+func LoadDragTableFile(fileName string) ([]DataPoint, error) {
+	var file, err = os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("DragTable: %w", err)
+	}
+	defer file.Close()
+
+	var dataPoints []DataPoint
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		dataPoints, err = LoadDragTableCSV(file)
+	case ".json":
+		dataPoints, err = LoadDragTableJSON(file)
+	default:
+		return nil, fmt.Errorf("DragTable: %w: the file %s is neither .csv nor .json file", ErrInvalidDragTable, fileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (file %s)", err, fileName)
+	}
+	return dataPoints, nil
+}
@@ -0,0 +1,19 @@
+package externalballistics
+
+import "errors"
+
+//ErrInvalidDragTable is returned (wrapped) when the drag table is unknown or the custom drag table is invalid
+var ErrInvalidDragTable = errors.New("invalid drag table")
+
+//ErrInvalidHumidity is returned (wrapped) when the humidity is out of the range
+var ErrInvalidHumidity = errors.New("humidity must be in 0..1 or 0..100 range")
+
+//ErrInvalidParameter is returned (wrapped) when a parameter passed to a constructor or a calculation is out of the valid range
+var ErrInvalidParameter = errors.New("invalid parameter")
+
+//ErrNonConvergence is returned (wrapped) when an iterative calculation doesn't reach the required accuracy
+var ErrNonConvergence = errors.New("the calculation doesn't converge")
+
+//ErrUnreachableTarget is returned (wrapped) when the projectile can't reach the distance
+//or the point of impact required
+var ErrUnreachableTarget = errors.New("the target is unreachable")
@@ -0,0 +1,123 @@
+package externalballistics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+)
+
+//FiringSolution keeps the sight adjustments required to hit the target and the state
+//of the projectile at the target
+type FiringSolution struct {
+	elevation       unit.Angular
+	windage         unit.Angular
+	elevationClicks float64
+	windageClicks   float64
+	timeOfFlight    Timespan
+	velocity        unit.Velocity
+	energy          unit.Energy
+}
+
+//Elevation returns the vertical adjustment of the sight
+//
+//The positive value means that the sight must be adjusted up (or the aim must be held over the target).
+func (v FiringSolution) Elevation() unit.Angular {
+	return v.elevation
+}
+
+//Windage returns the horizontal adjustment of the sight
+//
+//The positive value means that the sight must be adjusted right (or the aim must be held right of the target).
+func (v FiringSolution) Windage() unit.Angular {
+	return v.windage
+}
+
+//ElevationClicks returns the vertical adjustment expressed in the clicks of the scope
+//
+//The value isn't rounded. It is zero if the click value of the weapon isn't set (see Weapon.SetClickValue).
+func (v FiringSolution) ElevationClicks() float64 {
+	return v.elevationClicks
+}
+
+//WindageClicks returns the horizontal adjustment expressed in the clicks of the scope
+//
+//The value isn't rounded. It is zero if the click value of the weapon isn't set (see Weapon.SetClickValue).
+func (v FiringSolution) WindageClicks() float64 {
+	return v.windageClicks
+}
+
+//TimeOfFlight returns the time the projectile takes to reach the target
+func (v FiringSolution) TimeOfFlight() Timespan {
+	return v.timeOfFlight
+}
+
+//Velocity returns the velocity of the projectile at the target
+func (v FiringSolution) Velocity() unit.Velocity {
+	return v.velocity
+}
+
+//Energy returns the kinetic energy of the projectile at the target
+func (v FiringSolution) Energy() unit.Energy {
+	return v.energy
+}
+
+//CalculateFiringSolution calculates the sight adjustments required to hit the target at the distance
+//and the look angle specified
+//
+//slantRange - is the distance along the line of sight from the shooter to the target
+//
+//lookAngle - is the angle between the line of sight and the horizon. The positive angle
+//means that the target is higher and the negative angle means that the target is lower
+//
+//units - are the units of the angular adjustments, may be any value from unit.Angular* constants
+//
+//The weapon is expected to be zeroed as set in weapon.Zero().
+func (v TrajectoryCalculator) CalculateFiringSolution(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, windInfo []WindInfo, slantRange unit.Distance, lookAngle unit.Angular, units byte) (FiringSolution, error) {
+	if _, err := unit.CreateAngular(0, units); err != nil {
+		return FiringSolution{}, fmt.Errorf("FiringSolution: %w", err)
+	}
+	var distance = slantRange.In(unit.DistanceFoot)
+	if !isPositive(distance) {
+		return FiringSolution{}, fmt.Errorf("FiringSolution: %w, the slant range must be greater than zero", ErrInvalidParameter)
+	}
+	var angle = lookAngle.In(unit.AngularRadian)
+	if !(math.Abs(angle) < math.Pi/2) {
+		return FiringSolution{}, fmt.Errorf("FiringSolution: %w, the look angle must be between -90 and 90 degrees", ErrInvalidParameter)
+	}
+
+	sightAngle, err := v.SightAngleChecked(ammunition, weapon, atmosphere)
+	if err != nil {
+		return FiringSolution{}, fmt.Errorf("FiringSolution: %w", err)
+	}
+
+	var horizontalDistance = distance * math.Cos(angle)
+	var shotInfo = CreateShotParameterUnlevelForDistances(sightAngle,
+		[]unit.Distance{unit.MustCreateDistance(horizontalDistance, unit.DistanceFoot)},
+		lookAngle, unit.MustCreateAngular(0, unit.AngularRadian))
+	var data = v.CalculateTrajectory(ammunition, weapon, atmosphere, shotInfo, windInfo).Data()
+	if len(data) == 0 {
+		return FiringSolution{}, fmt.Errorf("FiringSolution: %w, the projectile doesn't reach the distance %s", ErrUnreachableTarget, slantRange)
+	}
+	var impact = data[0]
+
+	//the miss distances are measured at the target perpendicular to the line of sight
+	var verticalMiss = (impact.Drop().In(unit.DistanceFoot) - horizontalDistance*math.Tan(angle)) * math.Cos(angle)
+	var horizontalMiss = impact.Windage().In(unit.DistanceFoot)
+	var elevation = unit.MustCreateAngular(-math.Atan(verticalMiss/distance), unit.AngularRadian).Convert(units)
+	var windage = unit.MustCreateAngular(-math.Atan(horizontalMiss/distance), unit.AngularRadian).Convert(units)
+
+	var solution = FiringSolution{
+		elevation:    elevation,
+		windage:      windage,
+		timeOfFlight: impact.Time(),
+		velocity:     impact.Velocity(),
+		energy:       impact.Energy(),
+	}
+	var click = weapon.ClickValue().In(unit.AngularRadian)
+	if click > 0 {
+		solution.elevationClicks = elevation.In(unit.AngularRadian) / click
+		solution.windageClicks = windage.In(unit.AngularRadian) / click
+	}
+	return solution, nil
+}
@@ -0,0 +1,233 @@
+package externalballistics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/vector"
+)
+
+const cAdaptiveMinimumStepFraction float64 = 1e-6
+const cAdaptiveSafetyFactor float64 = 0.9
+const cAdaptiveMinimumScale float64 = 0.2
+const cAdaptiveMaximumScale float64 = 5.0
+
+//IntegrationState keeps the state of the projectile at one point of the numerical integration
+//
+//The position is in feet (x - distance towards target, y - drop and z - windage),
+//the velocity is in feet per second and the time is in seconds since the shot.
+type IntegrationState struct {
+	Time     float64
+	Position vector.Vector
+	Velocity vector.Vector
+	//NextStep is the size of the next step (in seconds) proposed by a variable step integrator
+	//
+	//The trajectory calculator passes the state returned back to the integrator, so the integrator
+	//may keep the step it has chosen here. Zero means that no step is proposed.
+	NextStep float64
+}
+
+//AccelerationFunction calculates the acceleration of the projectile (in feet per second squared)
+//at the position and with the velocity specified
+type AccelerationFunction func(position vector.Vector, velocity vector.Vector) vector.Vector
+
+//Integrator is a numerical method used by TrajectoryCalculator to advance the projectile along the trajectory
+type Integrator interface {
+	//Step advances the state by deltaTime seconds using the acceleration function specified
+	//
+	//An integrator which chooses the size of the step itself (see VariableStepIntegrator) may advance
+	//the state by less than deltaTime, so the time of the state returned must be used.
+	Step(state IntegrationState, deltaTime float64, acceleration AccelerationFunction) IntegrationState
+}
+
+//VariableStepIntegrator is an Integrator which chooses the size of the step itself
+//
+//TrajectoryCalculator asks such integrator to advance the projectile up to the next point required
+//(the next row, the change of the wind or the zero distance) instead of by the calculation step.
+type VariableStepIntegrator interface {
+	Integrator
+	//IsVariableStep returns the flag indicating whether the integrator may advance the state by less than deltaTime
+	IsVariableStep() bool
+}
+
+type eulerIntegrator struct {
+}
+
+//CreateEulerIntegrator creates the first order Euler integrator
+//
+//This is the default integrator of the trajectory calculator. It is the fastest one, but
+//its accuracy depends on the maximum calculator step size.
+func CreateEulerIntegrator() Integrator {
+	return eulerIntegrator{}
+}
+
+//Step advances the state by deltaTime seconds
+func (v eulerIntegrator) Step(state IntegrationState, deltaTime float64, acceleration AccelerationFunction) IntegrationState {
+	var velocity = state.Velocity.Add(acceleration(state.Position, state.Velocity).MultiplyByConst(deltaTime))
+	var deltaPosition = vector.Create(state.Velocity.X*deltaTime, velocity.Y*deltaTime, velocity.Z*deltaTime)
+	return IntegrationState{
+		Time:     state.Time + deltaPosition.Magnitude()/velocity.Magnitude(),
+		Position: state.Position.Add(deltaPosition),
+		Velocity: velocity,
+	}
+}
+
+type rungeKuttaIntegrator struct {
+}
+
+//CreateRungeKuttaIntegrator creates the classic fourth order Runge-Kutta integrator
+//
+//The integrator calculates acceleration four times per step, but produces accurate results
+//with a much larger maximum calculator step size than Euler integrator.
+func CreateRungeKuttaIntegrator() Integrator {
+	return rungeKuttaIntegrator{}
+}
+
+//Step advances the state by deltaTime seconds
+func (v rungeKuttaIntegrator) Step(state IntegrationState, deltaTime float64, acceleration AccelerationFunction) IntegrationState {
+	var halfTime = deltaTime / 2
+	var p1 = state.Velocity
+	var v1 = acceleration(state.Position, p1)
+	var p2 = state.Velocity.Add(v1.MultiplyByConst(halfTime))
+	var v2 = acceleration(state.Position.Add(p1.MultiplyByConst(halfTime)), p2)
+	var p3 = state.Velocity.Add(v2.MultiplyByConst(halfTime))
+	var v3 = acceleration(state.Position.Add(p2.MultiplyByConst(halfTime)), p3)
+	var p4 = state.Velocity.Add(v3.MultiplyByConst(deltaTime))
+	var v4 = acceleration(state.Position.Add(p3.MultiplyByConst(deltaTime)), p4)
+
+	return IntegrationState{
+		Time:     state.Time + deltaTime,
+		Position: state.Position.Add(p1.Add(p2.MultiplyByConst(2)).Add(p3.MultiplyByConst(2)).Add(p4).MultiplyByConst(deltaTime / 6)),
+		Velocity: state.Velocity.Add(v1.Add(v2.MultiplyByConst(2)).Add(v3.MultiplyByConst(2)).Add(v4).MultiplyByConst(deltaTime / 6)),
+	}
+}
+
+//Dormand-Prince 5(4) coefficients
+var dormandPrinceA = [][]float64{
+	{},
+	{1.0 / 5},
+	{3.0 / 40, 9.0 / 40},
+	{44.0 / 45, -56.0 / 15, 32.0 / 9},
+	{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+	{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+	{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+}
+
+var dormandPrinceB = []float64{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84, 0}
+
+var dormandPrinceE = []float64{
+	35.0/384 - 5179.0/57600,
+	0,
+	500.0/1113 - 7571.0/16695,
+	125.0/192 - 393.0/640,
+	-2187.0/6784 + 92097.0/339200,
+	11.0/84 - 187.0/2100,
+	-1.0 / 40,
+}
+
+type adaptiveIntegrator struct {
+	tolerance float64
+}
+
+//CreateAdaptiveIntegrator creates the adaptive Runge-Kutta (Dormand-Prince 5(4)) integrator
+//
+//The integrator chooses the size of each step to keep the estimated error of the projectile
+//position on the step within the tolerance specified, so the maximum calculator step size
+//doesn't limit the step of this integrator. The step is also limited so the trajectory deviates from
+//the straight line between the ends of the step by no more than the tolerance, because the points
+//of the trajectory between the steps are interpolated linearly.
+func CreateAdaptiveIntegrator(tolerance unit.Distance) (Integrator, error) {
+	if tolerance.In(unit.DistanceFoot) <= 0 {
+		return nil, fmt.Errorf("Integrator: %w, the tolerance must be greater than zero", ErrInvalidParameter)
+	}
+	return adaptiveIntegrator{tolerance: tolerance.In(unit.DistanceFoot)}, nil
+}
+
+//Step advances the state by one step, but not more than by deltaTime seconds
+//
+//The size of the step is chosen to keep the estimated error within the tolerance. The size proposed for
+//the next step is kept in the state returned, so the step grows while the error stays small.
+func (v adaptiveIntegrator) Step(state IntegrationState, deltaTime float64, acceleration AccelerationFunction) IntegrationState {
+	if deltaTime <= 0 || math.IsNaN(deltaTime) {
+		return state
+	}
+	var step = state.NextStep
+	if step <= 0 {
+		step = deltaTime
+	}
+	var minimumStep = deltaTime * cAdaptiveMinimumStepFraction
+	var maximumStep = math.Min(deltaTime, v.interpolationStep(state.Velocity, acceleration(state.Position, state.Velocity)))
+
+	for {
+		var size = math.Max(math.Min(step, maximumStep), minimumStep)
+		var next, estimatedError = v.tryStep(state, size, acceleration)
+
+		//the step is rejected as too large when the error can't be estimated
+		var scale = cAdaptiveMinimumScale
+		if estimatedError == 0 {
+			scale = cAdaptiveMaximumScale
+		} else if !math.IsNaN(estimatedError) && !math.IsInf(estimatedError, 0) {
+			scale = math.Min(cAdaptiveMaximumScale,
+				math.Max(cAdaptiveMinimumScale, cAdaptiveSafetyFactor*math.Pow(v.tolerance/estimatedError, 0.2)))
+		}
+
+		if estimatedError <= v.tolerance || size <= minimumStep {
+			next.NextStep = size * scale
+			//the limited step doesn't tell that the larger step is too large
+			if size < step && scale >= 1 {
+				next.NextStep = math.Max(next.NextStep, step)
+			}
+			return next
+		}
+		step = math.Max(size*scale, minimumStep)
+	}
+}
+
+//IsVariableStep returns true because the adaptive integrator chooses the size of the step itself
+func (v adaptiveIntegrator) IsVariableStep() bool {
+	return true
+}
+
+//interpolationStep returns the largest step (in seconds) within which the trajectory deviates
+//from the straight line by no more than the tolerance
+func (v adaptiveIntegrator) interpolationStep(velocity vector.Vector, acceleration vector.Vector) float64 {
+	var speed = velocity.Magnitude()
+	var normal = acceleration
+	if speed > 0 {
+		normal = acceleration.Subtract(velocity.MultiplyByConst(acceleration.MultiplyByVector(velocity) / (speed * speed)))
+	}
+	var curvature = normal.Magnitude()
+	if curvature == 0 || math.IsNaN(curvature) {
+		return math.Inf(1)
+	}
+	return math.Sqrt(8 * v.tolerance / curvature)
+}
+
+func (v adaptiveIntegrator) tryStep(state IntegrationState, step float64, acceleration AccelerationFunction) (IntegrationState, float64) {
+	const stages = 7
+	var dp, dv [stages]vector.Vector
+
+	for i := 0; i < stages; i++ {
+		var position = state.Position
+		var velocity = state.Velocity
+		for j, a := range dormandPrinceA[i] {
+			position = position.Add(dp[j].MultiplyByConst(a * step))
+			velocity = velocity.Add(dv[j].MultiplyByConst(a * step))
+		}
+		dp[i] = velocity
+		dv[i] = acceleration(position, velocity)
+	}
+
+	var position, velocity = state.Position, state.Velocity
+	var positionError, velocityError vector.Vector
+	for i := 0; i < stages; i++ {
+		position = position.Add(dp[i].MultiplyByConst(dormandPrinceB[i] * step))
+		velocity = velocity.Add(dv[i].MultiplyByConst(dormandPrinceB[i] * step))
+		positionError = positionError.Add(dp[i].MultiplyByConst(dormandPrinceE[i] * step))
+		velocityError = velocityError.Add(dv[i].MultiplyByConst(dormandPrinceE[i] * step))
+	}
+
+	var estimatedError = math.Max(positionError.Magnitude(), velocityError.Magnitude()*step)
+	return IntegrationState{Time: state.Time + step, Position: position, Velocity: velocity, NextStep: step}, estimatedError
+}
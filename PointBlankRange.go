@@ -0,0 +1,162 @@
+package externalballistics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+)
+
+const cPointBlankRangeAccuracy float64 = 0.0001
+const cMaxPointBlankRangeIterations int = 60
+const cPointBlankRangeMaximumDistance float64 = 15000
+
+//PointBlankRange keeps the maximum point blank range (MPBR) of the weapon and the ammunition,
+//i.e. the distance up to which the projectile stays within the vital zone of the target
+//when aiming at its center
+type PointBlankRange struct {
+	sightAngle              unit.Angular
+	pointBlankRange         unit.Distance
+	nearZero                unit.Distance
+	farZero                 unit.Distance
+	maximumOrdinate         unit.Distance
+	maximumOrdinateDistance unit.Distance
+}
+
+//SightAngle returns the sight angle at which the maximum point blank range is reached
+func (v PointBlankRange) SightAngle() unit.Angular {
+	return v.sightAngle
+}
+
+//ZeroDistance returns the optimal distance to zero the weapon at
+//
+//It is the same as the far zero distance.
+func (v PointBlankRange) ZeroDistance() unit.Distance {
+	return v.farZero
+}
+
+//Range returns the maximum point blank range
+func (v PointBlankRange) Range() unit.Distance {
+	return v.pointBlankRange
+}
+
+//NearZero returns the distance at which the projectile crosses the line of sight upwards
+func (v PointBlankRange) NearZero() unit.Distance {
+	return v.nearZero
+}
+
+//FarZero returns the distance at which the projectile crosses the line of sight downwards
+func (v PointBlankRange) FarZero() unit.Distance {
+	return v.farZero
+}
+
+//MaximumOrdinate returns the maximum height of the projectile over the line of sight
+//
+//It is a half of the vital zone size and is returned in the units of the vital zone.
+func (v PointBlankRange) MaximumOrdinate() unit.Distance {
+	return v.maximumOrdinate
+}
+
+//MaximumOrdinateDistance returns the distance at which the projectile reaches the maximum ordinate
+func (v PointBlankRange) MaximumOrdinateDistance() unit.Distance {
+	return v.maximumOrdinateDistance
+}
+
+//CalculatePointBlankRange calculates the maximum point blank range for the vital zone size (diameter) specified
+//
+//The sight angle is found so the trajectory of the level shot rises up to a half of the vital zone
+//above the line of sight. The maximum point blank range is the distance at which the projectile falls
+//a half of the vital zone below the line of sight. The zero of the weapon is ignored except that
+//the distances are returned in the units of the zero distance (yards if they aren't set).
+func (v TrajectoryCalculator) CalculatePointBlankRange(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, vitalZone unit.Distance) (PointBlankRange, error) {
+	var units = weapon.Zero().ZeroDistance().Units()
+	if units == 0 {
+		units = unit.DistanceYard
+	}
+	var halfZone = vitalZone.In(unit.DistanceFoot) / 2
+	if !isPositive(halfZone) {
+		return PointBlankRange{}, fmt.Errorf("PointBlankRange: %w, the vital zone must be greater than zero", ErrInvalidParameter)
+	}
+
+	//the trajectory is not needed once the projectile is well below the vital zone
+	var calculator = v
+	calculator.maximumDrop = unit.MustCreateDistance(math.Max(v.maximumDrop.In(unit.DistanceFoot),
+		-(2*halfZone+math.Abs(weapon.SightHeight().In(unit.DistanceFoot)))), unit.DistanceFoot)
+	//only the events and the visitor are used, so the trajectory has no rows except the muzzle
+	var maximumDistance = unit.MustCreateDistance(cPointBlankRangeMaximumDistance, unit.DistanceFoot)
+
+	//the distance at which the projectile falls below the vital zone, the projectile can
+	//cross the bottom of the vital zone downwards only after the apex
+	var fallDistance float64
+	var findFall = func(previous, point trajectoryPoint) bool {
+		if previous.drop >= -halfZone && point.drop < -halfZone {
+			var fraction = (previous.drop + halfZone) / (previous.drop - point.drop)
+			fallDistance = previous.interpolate(point, fraction).distance
+			return false
+		}
+		return true
+	}
+
+	var calculate = func(sightAngle float64) (TrajectoryResult, float64) {
+		var shotInfo = CreateShotParameters(unit.MustCreateAngular(sightAngle, unit.AngularRadian), maximumDistance, maximumDistance)
+		fallDistance = 0
+		var result = calculator.calculateTrajectory(ammunition, weapon, atmosphere, shotInfo, nil, findFall)
+		var apex = -weapon.SightHeight().In(unit.DistanceFoot)
+		for _, event := range result.Events() {
+			if event.Kind() == EventApex {
+				apex = event.Data().Drop().In(unit.DistanceFoot)
+			}
+		}
+		return result, apex - halfZone
+	}
+
+	//find the range of the sight angle which contains the solution
+	var low, high = 0.0, unit.MustCreateAngular(1, unit.AngularMOA).In(unit.AngularRadian)
+	var result, residual = calculate(high)
+	for residual < 0 {
+		low = high
+		high = high * 2
+		if high > math.Pi/4 {
+			return PointBlankRange{}, fmt.Errorf("PointBlankRange: %w, the projectile can't rise to a half of the vital zone %s", ErrUnreachableTarget, vitalZone)
+		}
+		result, residual = calculate(high)
+	}
+
+	var sightAngle = high
+	var iterations int
+	for math.Abs(residual) > cPointBlankRangeAccuracy {
+		iterations++
+		if iterations > cMaxPointBlankRangeIterations {
+			return PointBlankRange{}, fmt.Errorf("PointBlankRange: %w, the sight angle isn't found after %d iterations", ErrNonConvergence, iterations-1)
+		}
+		sightAngle = (low + high) / 2
+		result, residual = calculate(sightAngle)
+		if residual < 0 {
+			low = sightAngle
+		} else {
+			high = sightAngle
+		}
+	}
+
+	var pointBlankRange = PointBlankRange{
+		sightAngle:      unit.MustCreateAngular(sightAngle, unit.AngularRadian),
+		maximumOrdinate: unit.MustCreateDistance(halfZone, unit.DistanceFoot).Convert(vitalZone.Units()),
+	}
+	for _, event := range result.Events() {
+		var distance = event.Data().TravelledDistance().Convert(units)
+		switch event.Kind() {
+		case EventNearZero:
+			pointBlankRange.nearZero = distance
+		case EventFarZero:
+			pointBlankRange.farZero = distance
+		case EventApex:
+			pointBlankRange.maximumOrdinateDistance = distance
+		}
+	}
+
+	if fallDistance <= 0 {
+		return PointBlankRange{}, fmt.Errorf("PointBlankRange: %w, the projectile doesn't fall below the vital zone %s", ErrUnreachableTarget, vitalZone)
+	}
+	pointBlankRange.pointBlankRange = unit.MustCreateDistance(fallDistance, unit.DistanceFoot).Convert(units)
+	return pointBlankRange, nil
+}
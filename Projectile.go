@@ -1,6 +1,7 @@
 package externalballistics
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
@@ -30,6 +31,24 @@ func CreateProjectileWithDimensions(ballisticCoefficient BallisticCoefficient,
 		weight:         weight}
 }
 
+//CreateProjectileWithDimensionsChecked creates the description of a projectile with dimensions the same way
+//as CreateProjectileWithDimensions does, but returns an error if any of the parameters is invalid
+func CreateProjectileWithDimensionsChecked(ballisticCoefficient BallisticCoefficient,
+	bulletDiameter unit.Distance,
+	bulletLength unit.Distance,
+	weight unit.Weight) (Projectile, error) {
+	if err := validateProjectile(ballisticCoefficient, weight); err != nil {
+		return Projectile{}, err
+	}
+	if !isPositive(bulletDiameter.In(unit.DistanceInch)) {
+		return Projectile{}, fmt.Errorf("Projectile: %w, the bullet diameter must be greater than zero", ErrInvalidParameter)
+	}
+	if !isPositive(bulletLength.In(unit.DistanceInch)) {
+		return Projectile{}, fmt.Errorf("Projectile: %w, the bullet length must be greater than zero", ErrInvalidParameter)
+	}
+	return CreateProjectileWithDimensions(ballisticCoefficient, bulletDiameter, bulletLength, weight), nil
+}
+
 //CreateProjectile create projectile description without dimensions.
 //
 //If no dimensions set, the trajectory calculator won't be able to calculate spin drift.
@@ -40,6 +59,32 @@ func CreateProjectile(ballisticCoefficient BallisticCoefficient,
 		weight:        weight}
 }
 
+//CreateProjectileChecked creates projectile description without dimensions the same way as
+//CreateProjectile does, but returns an error if any of the parameters is invalid
+//
+//The ballistic coefficient expressed as form factor (FF) requires the bullet diameter, so
+//use CreateProjectileWithDimensionsChecked for such projectiles.
+func CreateProjectileChecked(ballisticCoefficient BallisticCoefficient,
+	weight unit.Weight) (Projectile, error) {
+	if err := validateProjectile(ballisticCoefficient, weight); err != nil {
+		return Projectile{}, err
+	}
+	if ballisticCoefficient.ValueType() == FF {
+		return Projectile{}, fmt.Errorf("Projectile: %w, the form factor requires the bullet diameter", ErrInvalidParameter)
+	}
+	return CreateProjectile(ballisticCoefficient, weight), nil
+}
+
+func validateProjectile(ballisticCoefficient BallisticCoefficient, weight unit.Weight) error {
+	if ballisticCoefficient.drag == nil || !isPositive(ballisticCoefficient.Value()) {
+		return fmt.Errorf("Projectile: %w, the ballistic coefficient isn't set", ErrInvalidParameter)
+	}
+	if !isPositive(weight.In(unit.WeightGrain)) {
+		return fmt.Errorf("Projectile: %w, the bullet weight must be greater than zero", ErrInvalidParameter)
+	}
+	return nil
+}
+
 //BallisticCoefficient returns ballistic coefficient of the projectile
 func (v Projectile) BallisticCoefficient() BallisticCoefficient {
 	return v.ballisticCoefficient
@@ -73,6 +118,15 @@ func (v Projectile) GetBallisticCoefficient() float64 {
 	return v.weight.In(unit.WeightGrain) / 7000.0 / math.Pow(v.bulletDiameter.In(unit.DistanceInch), 2) / v.ballisticCoefficient.value
 }
 
+//getBallisticCoefficientForVelocity returns the ballistic coefficient for the projectile
+//moving at the velocity specified (in feet per second)
+func (v Projectile) getBallisticCoefficientForVelocity(velocity float64) float64 {
+	if v.ballisticCoefficient.HasMultipleValues() {
+		return v.ballisticCoefficient.valueForVelocity(velocity)
+	}
+	return v.GetBallisticCoefficient()
+}
+
 //Ammunition struct keeps the des of ammunition (e.g. projectile loaded into a case shell)
 type Ammunition struct {
 	projectile     Projectile
@@ -87,6 +141,15 @@ func CreateAmmunition(bullet Projectile, muzzleVelocity unit.Velocity) Ammunitio
 	}
 }
 
+//CreateAmmunitionChecked creates the description of the ammunition the same way as CreateAmmunition does,
+//but returns an error if the muzzle velocity isn't greater than zero
+func CreateAmmunitionChecked(bullet Projectile, muzzleVelocity unit.Velocity) (Ammunition, error) {
+	if !isPositive(muzzleVelocity.In(unit.VelocityFPS)) {
+		return Ammunition{}, fmt.Errorf("Ammunition: %w, the muzzle velocity must be greater than zero", ErrInvalidParameter)
+	}
+	return CreateAmmunition(bullet, muzzleVelocity), nil
+}
+
 //Bullet returns the description of the projectile
 func (v Ammunition) Bullet() Projectile {
 	return v.projectile
@@ -1,6 +1,13 @@
 package externalballistics
 
-import "github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+)
 
 //ShotParameters struct keeps parameters of the shot to be calculated
 type ShotParameters struct {
@@ -9,6 +16,14 @@ type ShotParameters struct {
 	cantAngle       unit.Angular
 	maximumDistance unit.Distance
 	step            unit.Distance
+	hasTimeStep     bool
+	maximumTime     time.Duration
+	timeStep        time.Duration
+	hasCoriolis     bool
+	latitude        unit.Angular
+	azimuth         unit.Angular
+	hasDistances    bool
+	distances       []unit.Distance
 }
 
 //CreateShotParameters creates parameters of the shot
@@ -24,6 +39,18 @@ func CreateShotParameters(sightAngle unit.Angular, maxDistance unit.Distance, st
 	}
 }
 
+//CreateShotParametersChecked creates parameters of the shot the same way as CreateShotParameters does,
+//but returns an error if the maximum distance or the step is invalid
+func CreateShotParametersChecked(sightAngle unit.Angular, maxDistance unit.Distance, step unit.Distance) (ShotParameters, error) {
+	if err := validateShotAngles(sightAngle, unit.MustCreateAngular(0, unit.AngularRadian), unit.MustCreateAngular(0, unit.AngularRadian)); err != nil {
+		return ShotParameters{}, err
+	}
+	if err := validateShotDistance(maxDistance, step); err != nil {
+		return ShotParameters{}, err
+	}
+	return CreateShotParameters(sightAngle, maxDistance, step), nil
+}
+
 //SightAngle returns the angle of the sight
 func (v ShotParameters) SightAngle() unit.Angular {
 	return v.sightAngle
@@ -49,6 +76,22 @@ func (v ShotParameters) Step() unit.Distance {
 	return v.step
 }
 
+//HasTimeStep returns the flag indicating whether the calculation results are produced
+//at time steps instead of distance steps
+func (v ShotParameters) HasTimeStep() bool {
+	return v.hasTimeStep
+}
+
+//MaximumTime returns the maximum time of flight to be calculated
+func (v ShotParameters) MaximumTime() time.Duration {
+	return v.maximumTime
+}
+
+//TimeStep returns the time of flight between calculation results
+func (v ShotParameters) TimeStep() time.Duration {
+	return v.timeStep
+}
+
 //CreateShotParameterUnlevel creates the parameter of the shot aimed at the target which is not on th same level
 //as the shooter
 //
@@ -65,3 +108,229 @@ func CreateShotParameterUnlevel(sightAngle unit.Angular, maxDistance unit.Distan
 		step:            step,
 	}
 }
+
+//CreateShotParameterUnlevelChecked creates the parameter of the shot aimed at the target which is not on th same level
+//as the shooter the same way as CreateShotParameterUnlevel does, but returns an error if any of the parameters is invalid
+//
+//The shot angle must be between -90 and 90 degrees.
+func CreateShotParameterUnlevelChecked(sightAngle unit.Angular, maxDistance unit.Distance, step unit.Distance, shotAngle unit.Angular, cantAngle unit.Angular) (ShotParameters, error) {
+	if err := validateShotAngles(sightAngle, shotAngle, cantAngle); err != nil {
+		return ShotParameters{}, err
+	}
+	if err := validateShotDistance(maxDistance, step); err != nil {
+		return ShotParameters{}, err
+	}
+	return CreateShotParameterUnlevel(sightAngle, maxDistance, step, shotAngle, cantAngle), nil
+}
+
+//HasDistances returns the flag indicating whether the trajectory is calculated at the list of
+//distances instead of every distance step
+func (v ShotParameters) HasDistances() bool {
+	return v.hasDistances
+}
+
+//Distances returns the distances at which the trajectory is calculated ordered by distance
+func (v ShotParameters) Distances() []unit.Distance {
+	var distances = make([]unit.Distance, len(v.distances))
+	copy(distances, v.distances)
+	return distances
+}
+
+//HasCoriolis returns the flag indicating whether the Coriolis and Eötvös effects are calculated
+func (v ShotParameters) HasCoriolis() bool {
+	return v.hasCoriolis
+}
+
+//Latitude returns the latitude of the shooter
+func (v ShotParameters) Latitude() unit.Angular {
+	return v.latitude
+}
+
+//Azimuth returns the direction of the shot
+func (v ShotParameters) Azimuth() unit.Angular {
+	return v.azimuth
+}
+
+//SetCoriolis sets the shooter location and the direction of the shot which are required
+//to calculate the Coriolis and Eötvös effects.
+//
+//latitude - is the latitude of the shooter. The positive value means the northern hemisphere
+//and the negative value means the southern hemisphere
+//
+//azimuth - is the direction of the shot measured clockwise from the north, e.g. 0 degrees
+//means shooting to the north and 90 degrees means shooting to the east
+func (v *ShotParameters) SetCoriolis(latitude unit.Angular, azimuth unit.Angular) {
+	v.hasCoriolis = true
+	v.latitude = latitude
+	v.azimuth = azimuth
+}
+
+//CreateShotParametersByTime creates parameters of the shot which are calculated every time step of the flight
+//instead of every distance step
+//
+//sightAngle - is the angle between scope centerline and the barrel centerline
+func CreateShotParametersByTime(sightAngle unit.Angular, maxTime time.Duration, step time.Duration) ShotParameters {
+	return ShotParameters{
+		sightAngle:  sightAngle,
+		shotAngle:   unit.MustCreateAngular(0, unit.AngularRadian),
+		cantAngle:   unit.MustCreateAngular(0, unit.AngularRadian),
+		hasTimeStep: true,
+		maximumTime: maxTime,
+		timeStep:    step,
+	}
+}
+
+//CreateShotParameterUnlevelByTime creates the parameter of the shot aimed at the target which is not on th same level
+//as the shooter and which are calculated every time step of the flight instead of every distance step
+//
+//sightAngle - is the angle between scope centerline and the barrel centerline
+//
+//shotAngle - is the angle between lines drawn from the shooter to the target and the horizon. The positive angle
+//means that the target is higher and the negative angle means that the target is lower
+func CreateShotParameterUnlevelByTime(sightAngle unit.Angular, maxTime time.Duration, step time.Duration, shotAngle unit.Angular, cantAngle unit.Angular) ShotParameters {
+	return ShotParameters{
+		sightAngle:  sightAngle,
+		shotAngle:   shotAngle,
+		cantAngle:   cantAngle,
+		hasTimeStep: true,
+		maximumTime: maxTime,
+		timeStep:    step,
+	}
+}
+
+//CreateShotParametersByTimeChecked creates parameters of the shot which are calculated every time step
+//the same way as CreateShotParametersByTime does, but returns an error if the maximum time or the step is invalid
+func CreateShotParametersByTimeChecked(sightAngle unit.Angular, maxTime time.Duration, step time.Duration) (ShotParameters, error) {
+	if err := validateShotAngles(sightAngle, unit.MustCreateAngular(0, unit.AngularRadian), unit.MustCreateAngular(0, unit.AngularRadian)); err != nil {
+		return ShotParameters{}, err
+	}
+	if err := validateShotTime(maxTime, step); err != nil {
+		return ShotParameters{}, err
+	}
+	return CreateShotParametersByTime(sightAngle, maxTime, step), nil
+}
+
+//CreateShotParameterUnlevelByTimeChecked creates the parameter of the unlevel shot which are calculated every time step
+//the same way as CreateShotParameterUnlevelByTime does, but returns an error if any of the parameters is invalid
+//
+//The shot angle must be between -90 and 90 degrees.
+func CreateShotParameterUnlevelByTimeChecked(sightAngle unit.Angular, maxTime time.Duration, step time.Duration, shotAngle unit.Angular, cantAngle unit.Angular) (ShotParameters, error) {
+	if err := validateShotAngles(sightAngle, shotAngle, cantAngle); err != nil {
+		return ShotParameters{}, err
+	}
+	if err := validateShotTime(maxTime, step); err != nil {
+		return ShotParameters{}, err
+	}
+	return CreateShotParameterUnlevelByTime(sightAngle, maxTime, step, shotAngle, cantAngle), nil
+}
+
+//CreateShotParametersForDistances creates parameters of the shot which are calculated at the distances
+//specified instead of every distance step, e.g. to make a range card
+//
+//sightAngle - is the angle between scope centerline and the barrel centerline
+//
+//The trajectory contains exactly one point for every distance. The points are ordered by distance.
+func CreateShotParametersForDistances(sightAngle unit.Angular, distances []unit.Distance) ShotParameters {
+	return CreateShotParameterUnlevelForDistances(sightAngle, distances, unit.MustCreateAngular(0, unit.AngularRadian), unit.MustCreateAngular(0, unit.AngularRadian))
+}
+
+//CreateShotParameterUnlevelForDistances creates the parameter of the shot aimed at the target which is not on th same level
+//as the shooter and which are calculated at the distances specified instead of every distance step
+//
+//sightAngle - is the angle between scope centerline and the barrel centerline
+//
+//shotAngle - is the angle between lines drawn from the shooter to the target and the horizon. The positive angle
+//means that the target is higher and the negative angle means that the target is lower
+func CreateShotParameterUnlevelForDistances(sightAngle unit.Angular, distances []unit.Distance, shotAngle unit.Angular, cantAngle unit.Angular) ShotParameters {
+	var sorted = make([]unit.Distance, len(distances))
+	copy(sorted, distances)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].In(unit.DistanceFoot) < sorted[j].In(unit.DistanceFoot)
+	})
+
+	//the smallest interval between the distances defines the accuracy of the calculation
+	var maximumDistance, step float64
+	for _, distance := range sorted {
+		var interval = distance.In(unit.DistanceFoot) - maximumDistance
+		if interval > 0 && (step == 0 || interval < step) {
+			step = interval
+		}
+		maximumDistance = math.Max(maximumDistance, distance.In(unit.DistanceFoot))
+	}
+	if step == 0 {
+		step = 1
+	}
+
+	return ShotParameters{
+		sightAngle:      sightAngle,
+		shotAngle:       shotAngle,
+		cantAngle:       cantAngle,
+		maximumDistance: unit.MustCreateDistance(maximumDistance, unit.DistanceFoot),
+		step:            unit.MustCreateDistance(step, unit.DistanceFoot),
+		hasDistances:    true,
+		distances:       sorted,
+	}
+}
+
+//CreateShotParametersForDistancesChecked creates parameters of the shot which are calculated at the distances
+//specified the same way as CreateShotParametersForDistances does, but returns an error if the list of distances
+//is empty or any distance is negative
+func CreateShotParametersForDistancesChecked(sightAngle unit.Angular, distances []unit.Distance) (ShotParameters, error) {
+	return CreateShotParameterUnlevelForDistancesChecked(sightAngle, distances, unit.MustCreateAngular(0, unit.AngularRadian), unit.MustCreateAngular(0, unit.AngularRadian))
+}
+
+//CreateShotParameterUnlevelForDistancesChecked creates the parameter of the unlevel shot which are calculated at the distances
+//specified the same way as CreateShotParameterUnlevelForDistances does, but returns an error if any of the parameters is invalid
+//
+//The shot angle must be between -90 and 90 degrees.
+func CreateShotParameterUnlevelForDistancesChecked(sightAngle unit.Angular, distances []unit.Distance, shotAngle unit.Angular, cantAngle unit.Angular) (ShotParameters, error) {
+	if err := validateShotAngles(sightAngle, shotAngle, cantAngle); err != nil {
+		return ShotParameters{}, err
+	}
+	if len(distances) == 0 {
+		return ShotParameters{}, fmt.Errorf("ShotParameters: %w, at least one distance must be specified", ErrInvalidParameter)
+	}
+	for _, distance := range distances {
+		var feet = distance.In(unit.DistanceFoot)
+		if !isFinite(feet) || feet < 0 {
+			return ShotParameters{}, fmt.Errorf("ShotParameters: %w, the distance %s must not be negative", ErrInvalidParameter, distance)
+		}
+	}
+	return CreateShotParameterUnlevelForDistances(sightAngle, distances, shotAngle, cantAngle), nil
+}
+
+func validateShotAngles(sightAngle unit.Angular, shotAngle unit.Angular, cantAngle unit.Angular) error {
+	if !isFinite(sightAngle.In(unit.AngularRadian)) || !isFinite(cantAngle.In(unit.AngularRadian)) {
+		return fmt.Errorf("ShotParameters: %w, the sight angle and the cant angle must be finite numbers", ErrInvalidParameter)
+	}
+	if !(math.Abs(shotAngle.In(unit.AngularRadian)) < math.Pi/2) {
+		return fmt.Errorf("ShotParameters: %w, the shot angle must be between -90 and 90 degrees", ErrInvalidParameter)
+	}
+	return nil
+}
+
+func validateShotDistance(maxDistance unit.Distance, step unit.Distance) error {
+	if !isPositive(maxDistance.In(unit.DistanceFoot)) {
+		return fmt.Errorf("ShotParameters: %w, the maximum distance must be greater than zero", ErrInvalidParameter)
+	}
+	if !isPositive(step.In(unit.DistanceFoot)) {
+		return fmt.Errorf("ShotParameters: %w, the step must be greater than zero", ErrInvalidParameter)
+	}
+	if step.In(unit.DistanceFoot) > maxDistance.In(unit.DistanceFoot) {
+		return fmt.Errorf("ShotParameters: %w, the step must not be greater than the maximum distance", ErrInvalidParameter)
+	}
+	return nil
+}
+
+func validateShotTime(maxTime time.Duration, step time.Duration) error {
+	if maxTime <= 0 {
+		return fmt.Errorf("ShotParameters: %w, the maximum time must be greater than zero", ErrInvalidParameter)
+	}
+	if step <= 0 {
+		return fmt.Errorf("ShotParameters: %w, the time step must be greater than zero", ErrInvalidParameter)
+	}
+	if step > maxTime {
+		return fmt.Errorf("ShotParameters: %w, the time step must not be greater than the maximum time", ErrInvalidParameter)
+	}
+	return nil
+}
@@ -1,7 +1,9 @@
 package externalballistics
 
 import (
+	"fmt"
 	"math"
+	"sort"
 
 	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
 	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/vector"
@@ -12,10 +14,20 @@ const cMinimumVelocity float64 = 50.0
 const cMaximumDrop float64 = -15000
 const cMaxIterations int = 10
 const cGravityConstant float64 = -32.17405
+const cEarthAngularVelocity float64 = 7.292115e-5
 
 //TrajectoryCalculator table is used to calculate the trajectory of a projectile shot with the parameters specified
 type TrajectoryCalculator struct {
 	maximumCalculatorStepSize unit.Distance
+	integrator                Integrator
+	hasGroundLevel            bool
+	groundLevel               unit.Distance
+	minimumVelocity           unit.Velocity
+	maximumDrop               unit.Distance
+	maxIterations             int
+	zeroFindingAccuracy       unit.Distance
+	hasEnergyThreshold        bool
+	energyThreshold           unit.Energy
 }
 
 //MaximumCalculatorStepSize returns the maximum size of one calculation iteration.
@@ -33,6 +45,131 @@ func (v *TrajectoryCalculator) SetMaximumCalculatorStepSize(x unit.Distance) {
 	v.maximumCalculatorStepSize = x
 }
 
+//Integrator returns the numerical integrator used to calculate the trajectory
+func (v TrajectoryCalculator) Integrator() Integrator {
+	return v.integrator
+}
+
+//SetIntegrator sets the numerical integrator used to calculate the trajectory
+//
+//Euler integrator (see CreateEulerIntegrator) is used by default. Runge-Kutta integrator
+//(see CreateRungeKuttaIntegrator) is slower per step but produces stable results with a larger
+//maximum calculator step size. The adaptive integrator (see CreateAdaptiveIntegrator) chooses
+//the step itself, so the maximum calculator step size doesn't need to be tuned for it.
+//An integrator of your own which chooses the step itself should implement VariableStepIntegrator.
+func (v *TrajectoryCalculator) SetIntegrator(integrator Integrator) {
+	v.integrator = integrator
+}
+
+//HasGroundLevel returns the flag indicating whether the ground level is set
+func (v TrajectoryCalculator) HasGroundLevel() bool {
+	return v.hasGroundLevel
+}
+
+//GroundLevel returns the level of the ground
+func (v TrajectoryCalculator) GroundLevel() unit.Distance {
+	return v.groundLevel
+}
+
+//SetGroundLevel sets the level of the ground relative to the line of sight at the muzzle
+//
+//The negative value means that the ground is below the line of sight. When the ground level
+//is set, the calculation of the trajectory is terminated as soon as the descending projectile
+//reaches the ground.
+func (v *TrajectoryCalculator) SetGroundLevel(level unit.Distance) {
+	v.hasGroundLevel = true
+	v.groundLevel = level
+}
+
+//MinimumVelocity returns the velocity at which the calculation of the trajectory is terminated
+func (v TrajectoryCalculator) MinimumVelocity() unit.Velocity {
+	return v.minimumVelocity
+}
+
+//SetMinimumVelocity sets the velocity at which the calculation of the trajectory is terminated
+//
+//The default value is 50 feet per second. The velocity must not be negative.
+func (v *TrajectoryCalculator) SetMinimumVelocity(velocity unit.Velocity) error {
+	if velocity.In(unit.VelocityFPS) < 0 {
+		return fmt.Errorf("TrajectoryCalculator: %w, the minimum velocity must not be negative", ErrInvalidParameter)
+	}
+	v.minimumVelocity = velocity
+	return nil
+}
+
+//MaximumDrop returns the drop at which the calculation of the trajectory is terminated
+func (v TrajectoryCalculator) MaximumDrop() unit.Distance {
+	return v.maximumDrop
+}
+
+//SetMaximumDrop sets the drop at which the calculation of the trajectory is terminated
+//
+//The default value is -15000 feet. The drop must be less than zero.
+func (v *TrajectoryCalculator) SetMaximumDrop(drop unit.Distance) error {
+	if drop.In(unit.DistanceFoot) >= 0 {
+		return fmt.Errorf("TrajectoryCalculator: %w, the maximum drop must be less than zero", ErrInvalidParameter)
+	}
+	v.maximumDrop = drop
+	return nil
+}
+
+//MaxIterations returns the maximum number of iterations used to find the sight angle
+func (v TrajectoryCalculator) MaxIterations() int {
+	return v.maxIterations
+}
+
+//SetMaxIterations sets the maximum number of iterations used to find the sight angle
+//
+//The default value is 10. The number must be greater than zero.
+func (v *TrajectoryCalculator) SetMaxIterations(iterations int) error {
+	if iterations < 1 {
+		return fmt.Errorf("TrajectoryCalculator: %w, the maximum number of iterations must be greater than zero", ErrInvalidParameter)
+	}
+	v.maxIterations = iterations
+	return nil
+}
+
+//ZeroFindingAccuracy returns the accuracy with which the sight angle is found
+func (v TrajectoryCalculator) ZeroFindingAccuracy() unit.Distance {
+	return v.zeroFindingAccuracy
+}
+
+//SetZeroFindingAccuracy sets the accuracy with which the sight angle is found
+//
+//The sight angle is found when the projectile is closer than the accuracy to the line
+//of sight at the zero distance. The default value is 0.000005 feet. The accuracy must be
+//greater than zero.
+func (v *TrajectoryCalculator) SetZeroFindingAccuracy(accuracy unit.Distance) error {
+	if accuracy.In(unit.DistanceFoot) <= 0 {
+		return fmt.Errorf("TrajectoryCalculator: %w, the zero finding accuracy must be greater than zero", ErrInvalidParameter)
+	}
+	v.zeroFindingAccuracy = accuracy
+	return nil
+}
+
+//HasEnergyThreshold returns the flag indicating whether the energy threshold is set
+func (v TrajectoryCalculator) HasEnergyThreshold() bool {
+	return v.hasEnergyThreshold
+}
+
+//EnergyThreshold returns the energy threshold
+func (v TrajectoryCalculator) EnergyThreshold() unit.Energy {
+	return v.energyThreshold
+}
+
+//SetEnergyThreshold sets the energy at which EventEnergyThreshold event is reported
+//(see TrajectoryResult.Events), e.g. the minimum energy required to hunt the game
+//
+//The energy must be greater than zero.
+func (v *TrajectoryCalculator) SetEnergyThreshold(energy unit.Energy) error {
+	if !isPositive(energy.In(unit.EnergyFootPound)) {
+		return fmt.Errorf("TrajectoryCalculator: %w, the energy threshold must be greater than zero", ErrInvalidParameter)
+	}
+	v.hasEnergyThreshold = true
+	v.energyThreshold = energy
+	return nil
+}
+
 func (v TrajectoryCalculator) getCalculationStep(step float64) float64 {
 	step = step / 2 //do it twice for increased accuracy of velocity calculation and 10 times per step
 	var maximumStep = v.maximumCalculatorStepSize.In(unit.DistanceFoot)
@@ -46,24 +183,62 @@ func (v TrajectoryCalculator) getCalculationStep(step float64) float64 {
 	return step
 }
 
+//isVariableStep returns the flag indicating whether the integrator chooses the size of the step itself
+//
+//Such integrator is asked to advance the projectile up to the next point required instead of by the calculation step.
+func isVariableStep(integrator Integrator) bool {
+	var variable, ok = integrator.(VariableStepIntegrator)
+	return ok && variable.IsVariableStep()
+}
+
 //CreateTrajectoryCalculator creates and instance of the trajectory calculator
 func CreateTrajectoryCalculator() TrajectoryCalculator {
 	return TrajectoryCalculator{
 		maximumCalculatorStepSize: unit.MustCreateDistance(1, unit.DistanceFoot),
+		integrator:                CreateEulerIntegrator(),
+		minimumVelocity:           unit.MustCreateVelocity(cMinimumVelocity, unit.VelocityFPS),
+		maximumDrop:               unit.MustCreateDistance(cMaximumDrop, unit.DistanceFoot),
+		maxIterations:             cMaxIterations,
+		zeroFindingAccuracy:       unit.MustCreateDistance(cZeroFindingAccuracy, unit.DistanceFoot),
 	}
 }
 
 //SightAngle calculates the sight angle for a rifle with scope height specified and zeroed using the ammo specified at
 //the range specified and under the conditions (atmosphere) specified.
 //
+//If the weapon's zero information contains another ammunition or other conditions (see
+//CreateZeroInfoWithAnotherAmmoAndAtmosphere), the sight angle is calculated using them instead
+//of the ammunition and atmosphere passed. The trajectory calculated with the returned angle and
+//the current ammunition and atmosphere then shows the shift of the point of impact.
+//
 //The calculated value is to be used as sightAngle parameter of the ShotParameters structure
+//
+//The method returns the best angle found even if the zero can't be found with the required accuracy,
+//use SightAngleChecked to detect such cases.
 func (v TrajectoryCalculator) SightAngle(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere) unit.Angular {
+	var sightAngle, _ = v.SightAngleChecked(ammunition, weapon, atmosphere)
+	return sightAngle
+}
+
+//SightAngleChecked calculates the sight angle the same way as SightAngle does, but also reports
+//whether the angle is found
+//
+//If the projectile can't reach the zero distance or the zero can't be found with the required accuracy
+//within the maximum number of iterations, the method returns the best angle found and *ZeroFindingError.
+func (v TrajectoryCalculator) SightAngleChecked(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere) (unit.Angular, error) {
+	if weapon.Zero().HasAmmunition() {
+		ammunition = weapon.Zero().Ammunition()
+	}
+	if weapon.Zero().HasAtmosphere() {
+		atmosphere = weapon.Zero().Atmosphere()
+	}
+
 	var calculationStep = v.getCalculationStep(unit.MustCreateDistance(10, weapon.Zero().ZeroDistance().Units()).In(unit.DistanceFoot))
 
-	var deltaRangeVector, rangeVector, velocityVector, gravityVector vector.Vector
-	var muzzleVelocity, velocity, barrelAzimuth, barrelElevation float64
-	var densityFactor, mach, drag, zeroFindingError float64
-	var time, deltaTime float64
+	var state IntegrationState
+	var gravityVector vector.Vector
+	var muzzleVelocity, barrelAzimuth, barrelElevation float64
+	var densityFactor, mach, zeroFindingError float64
 	var maximumRange float64
 
 	mach = atmosphere.Mach().In(unit.VelocityFPS)
@@ -72,60 +247,128 @@ func (v TrajectoryCalculator) SightAngle(ammunition Ammunition, weapon Weapon, a
 	barrelAzimuth = 0.0
 	barrelElevation = 0
 
-	zeroFindingError = cZeroFindingAccuracy * 2
+	var zeroFindingAccuracy = v.zeroFindingAccuracy.In(unit.DistanceFoot)
+	var minimumVelocity = v.minimumVelocity.In(unit.VelocityFPS)
+	var maximumDrop = v.maximumDrop.In(unit.DistanceFoot)
+	zeroFindingError = zeroFindingAccuracy * 2
 	var iterationsCount int
 	var bullet = ammunition.Bullet()
-	var ballisticFactor = 1 / bullet.GetBallisticCoefficient()
 
 	gravityVector = vector.Create(0, cGravityConstant, 0)
-	for zeroFindingError > cZeroFindingAccuracy && iterationsCount < cMaxIterations {
-		velocity = muzzleVelocity
-		time = 0.0
+	var acceleration = func(position vector.Vector, velocity vector.Vector) vector.Vector {
+		var speed = velocity.Magnitude()
+		var ballisticFactor = 1 / bullet.getBallisticCoefficientForVelocity(speed)
+		var drag = ballisticFactor * densityFactor * speed * bullet.BallisticCoefficient().Drag(speed/mach)
+		return gravityVector.Subtract(velocity.MultiplyByConst(drag))
+	}
 
+	var zeroDistance = weapon.Zero().ZeroDistance().In(unit.DistanceFoot)
+	var zeroUnits = weapon.Zero().ZeroDistance().Units()
+	var variableStep = isVariableStep(v.integrator)
+
+	for zeroFindingError > zeroFindingAccuracy && iterationsCount < v.maxIterations {
 		//x - distance towards target,
 		//y - drop and
 		//z - windage
-		rangeVector = vector.Create(0.0, -weapon.SightHeight().In(unit.DistanceFoot), 0)
-		velocityVector = vector.Create(math.Cos(barrelElevation)*math.Cos(barrelAzimuth), math.Sin(barrelElevation), math.Cos(barrelElevation)*math.Sin(barrelAzimuth)).MultiplyByConst(velocity)
-		var zeroDistance = weapon.Zero().ZeroDistance().In(unit.DistanceFoot)
+		state = IntegrationState{
+			Time:     0,
+			Position: vector.Create(0.0, -weapon.SightHeight().In(unit.DistanceFoot), 0),
+			Velocity: vector.Create(math.Cos(barrelElevation)*math.Cos(barrelAzimuth), math.Sin(barrelElevation), math.Cos(barrelElevation)*math.Sin(barrelAzimuth)).MultiplyByConst(muzzleVelocity),
+		}
 		maximumRange = zeroDistance + calculationStep
+		var reached = false
 
-		for rangeVector.X <= maximumRange {
-			if velocity < cMinimumVelocity || rangeVector.Y < cMaximumDrop {
+		for state.Position.X <= maximumRange {
+			if state.Velocity.Magnitude() < minimumVelocity || state.Position.Y < maximumDrop {
 				break
 			}
 
-			deltaTime = calculationStep / velocityVector.X
-			velocity = velocityVector.Magnitude()
-			drag = ballisticFactor * densityFactor * velocity * bullet.BallisticCoefficient().Drag(velocity/mach)
-			velocityVector = velocityVector.Subtract((velocityVector.MultiplyByConst(drag).Subtract(gravityVector)).MultiplyByConst(deltaTime))
-			deltaRangeVector = vector.Create(calculationStep, velocityVector.Y*deltaTime, velocityVector.Z*deltaTime)
-			rangeVector = rangeVector.Add(deltaRangeVector)
-			velocity = velocityVector.Magnitude()
-			time = time + deltaRangeVector.Magnitude()/velocity
-
-			if math.Abs(rangeVector.X-zeroDistance) < 0.5*calculationStep {
-				zeroFindingError = math.Abs(rangeVector.Y)
-				barrelElevation = barrelElevation - rangeVector.Y/rangeVector.X
+			var stepDistance = calculationStep
+			if variableStep {
+				stepDistance = math.Max(zeroDistance-state.Position.X, calculationStep)
+			}
+			state = v.integrator.Step(state, stepDistance/state.Velocity.X, acceleration)
+
+			if math.Abs(state.Position.X-zeroDistance) < 0.5*calculationStep {
+				zeroFindingError = math.Abs(state.Position.Y)
+				barrelElevation = barrelElevation - state.Position.Y/state.Position.X
+				reached = true
 				break
 			}
 		}
 		iterationsCount++
+
+		if !reached {
+			return unit.MustCreateAngular(barrelElevation, unit.AngularRadian), &ZeroFindingError{
+				reason:     ZeroFindingUnreachable,
+				residual:   unit.MustCreateDistance(zeroDistance-state.Position.X, unit.DistanceFoot).Convert(zeroUnits),
+				iterations: iterationsCount,
+			}
+		}
+	}
+
+	var sightAngle = unit.MustCreateAngular(barrelElevation, unit.AngularRadian)
+	if zeroFindingError > zeroFindingAccuracy {
+		return sightAngle, &ZeroFindingError{
+			reason:     ZeroFindingNonConvergence,
+			residual:   unit.MustCreateDistance(zeroFindingError, unit.DistanceFoot).Convert(zeroUnits),
+			iterations: iterationsCount,
+		}
 	}
-	return unit.MustCreateAngular(barrelElevation, unit.AngularRadian)
+	return sightAngle, nil
 }
 
 //Trajectory calculates the trajectory with the parameters specified
+//
+//The trajectory points are calculated at every distance step or, if the shot parameters
+//are created with a time step (see CreateShotParametersByTime), at every time step of the flight
+//or, if the shot parameters are created with a list of distances (see CreateShotParametersForDistances),
+//at every distance of the list. The points are interpolated between the integration steps exactly at the distance (or time) of the step.
+//
+//If the calculation is terminated earlier (see CalculateTrajectory), only the points
+//calculated before the termination are returned.
 func (v TrajectoryCalculator) Trajectory(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, shotInfo ShotParameters, windInfo []WindInfo) []TrajectoryData {
-	var rangeTo = shotInfo.MaximumDistance().In(unit.DistanceFoot)
-	var step = shotInfo.Step().In(unit.DistanceFoot)
+	return v.calculateTrajectory(ammunition, weapon, atmosphere, shotInfo, windInfo, nil).Data()
+}
 
-	var calculationStep = v.getCalculationStep(step)
+//CalculateTrajectory calculates the trajectory with the parameters specified and reports
+//the reason why the calculation ended
+func (v TrajectoryCalculator) CalculateTrajectory(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, shotInfo ShotParameters, windInfo []WindInfo) TrajectoryResult {
+	var result = v.calculateTrajectory(ammunition, weapon, atmosphere, shotInfo, windInfo, nil)
+	result.trace = func(visit trajectoryVisitor) {
+		v.calculateTrajectory(ammunition, weapon, atmosphere, shotInfo, windInfo, visit)
+	}
+	return result
+}
 
-	var deltaRangeVector, rangeVector, velocityAdjusted, velocityVector, windVector, gravityVector vector.Vector
-	var muzzleVelocity, velocity, barrelAzimuth, barrelElevation float64
-	var densityFactor, mach, drag float64
-	var time, deltaTime float64
+//trajectoryVisitor is called for every integration step of the trajectory with the previous
+//and the current integration points, the calculation stops when the visitor returns false
+type trajectoryVisitor func(previous, point trajectoryPoint) bool
+
+//calculateTrajectory calculates the trajectory and calls the visitor, if specified, for every integration step
+//
+//The integration points aren't kept, so the visitor is the way to look at the trajectory between the rows.
+func (v TrajectoryCalculator) calculateTrajectory(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, shotInfo ShotParameters, windInfo []WindInfo, visit trajectoryVisitor) TrajectoryResult {
+	var muzzleVelocity = ammunition.MuzzleVelocity().In(unit.VelocityFPS)
+	var rangeTo, step, calculationStep, calculationTime float64
+
+	//when the time step is set, the trajectory is sampled and limited by the time of flight
+	//instead of the distance
+	var byTime = shotInfo.HasTimeStep()
+	if byTime {
+		rangeTo = shotInfo.MaximumTime().Seconds()
+		step = shotInfo.TimeStep().Seconds()
+		calculationTime = v.getCalculationStep(step*muzzleVelocity) / muzzleVelocity
+	} else {
+		rangeTo = shotInfo.MaximumDistance().In(unit.DistanceFoot)
+		step = shotInfo.Step().In(unit.DistanceFoot)
+		calculationStep = v.getCalculationStep(step)
+	}
+
+	var state IntegrationState
+	var windVector, gravityVector vector.Vector
+	var velocity, barrelAzimuth, barrelElevation float64
+	var mach float64
 	var maximumRange, nextRangeDistance float64
 	var bulletWeight = ammunition.Bullet().BulletWeight().In(unit.WeightGrain)
 	var stabilityCoefficient = 1.0
@@ -137,6 +380,17 @@ func (v TrajectoryCalculator) Trajectory(ammunition Ammunition, weapon Weapon, a
 	}
 
 	var rangesLength = int(math.Floor(rangeTo/step)) + 1
+	if byTime {
+		//the durations are divided exactly, 300ms by 100ms is 3 steps, not 2.9999999999999996
+		rangesLength = int(shotInfo.MaximumTime()/shotInfo.TimeStep()) + 1
+	}
+	var distances []float64
+	if shotInfo.HasDistances() {
+		for _, distance := range shotInfo.Distances() {
+			distances = append(distances, distance.In(unit.DistanceFoot))
+		}
+		rangesLength = len(distances)
+	}
 	var ranges = make([]TrajectoryData, rangesLength)
 
 	barrelAzimuth = 0.0
@@ -155,20 +409,34 @@ func (v TrajectoryCalculator) Trajectory(ammunition Ammunition, weapon Weapon, a
 		windVector = windToVector(shotInfo, windInfo[0])
 	}
 
-	muzzleVelocity = ammunition.MuzzleVelocity().In(unit.VelocityFPS)
 	gravityVector = vector.Create(0, cGravityConstant, 0)
 	velocity = muzzleVelocity
-	time = 0.0
 
 	//x - distance towards target,
 	//y - drop and
 	//z - windage
-	rangeVector = vector.Create(0.0, -weapon.SightHeight().In(unit.DistanceFoot), 0)
-	velocityVector = vector.Create(math.Cos(barrelElevation)*math.Cos(barrelAzimuth), math.Sin(barrelElevation), math.Cos(barrelElevation)*math.Sin(barrelAzimuth)).MultiplyByConst(velocity)
+	state = IntegrationState{
+		Time:     0,
+		Position: vector.Create(0.0, -weapon.SightHeight().In(unit.DistanceFoot), 0),
+		Velocity: vector.Create(math.Cos(barrelElevation)*math.Cos(barrelAzimuth), math.Sin(barrelElevation), math.Cos(barrelElevation)*math.Sin(barrelAzimuth)).MultiplyByConst(velocity),
+	}
 
 	var currentItem int
+	var previous trajectoryPoint
+	var hasPrevious bool
+	var events []TrajectoryEvent
+	var lineOfSightSlope = math.Tan(shotInfo.ShotAngle().In(unit.AngularRadian))
+	var energyThreshold = v.energyThreshold.In(unit.EnergyFootPound)
+	var previousSlope float64
+	var termination = TerminationMaximumRange
+	var groundLevel = v.groundLevel.In(unit.DistanceFoot)
+	var minimumVelocity = v.minimumVelocity.In(unit.VelocityFPS)
+	var maximumDrop = v.maximumDrop.In(unit.DistanceFoot)
 	maximumRange = rangeTo
 	nextRangeDistance = 0
+	if len(distances) > 0 {
+		nextRangeDistance = distances[0]
+	}
 
 	var twistCoefficient float64
 
@@ -180,20 +448,75 @@ func (v TrajectoryCalculator) Trajectory(ammunition Ammunition, weapon Weapon, a
 		}
 	}
 
+	var aerodynamicJump float64
+	if calculateDrift && len(windInfo) > 0 {
+		aerodynamicJump = calculateAerodynamicJump(ammunition, weapon, windInfo[0], stabilityCoefficient)
+	}
+
 	var bullet = ammunition.Bullet()
-	var ballisticFactor = 1 / bullet.GetBallisticCoefficient()
+
+	var acceleration = func(position vector.Vector, velocity vector.Vector) vector.Vector {
+		var densityFactor, mach = atmosphere.getDensityFactorAndMachForAltitude(alt0 + position.Y)
+		var velocityAdjusted = velocity.Subtract(windVector)
+		var speed = velocityAdjusted.Magnitude()
+		var ballisticFactor = 1 / bullet.getBallisticCoefficientForVelocity(speed)
+		var drag = ballisticFactor * densityFactor * speed * bullet.BallisticCoefficient().Drag(speed/mach)
+		return gravityVector.Subtract(velocityAdjusted.MultiplyByConst(drag))
+	}
+
+	//when Coriolis effect is calculated, the trajectory without it is calculated
+	//along to find the deflection caused by the Earth rotation
+	var calculateCoriolis = shotInfo.HasCoriolis()
+	var referenceState = state
+	var referenceAcceleration = acceleration
+	if calculateCoriolis {
+		var earthRotation = earthRotationVector(shotInfo.Latitude(), shotInfo.Azimuth())
+		acceleration = func(position vector.Vector, velocity vector.Vector) vector.Vector {
+			return referenceAcceleration(position, velocity).Subtract(earthRotation.CrossProduct(velocity).MultiplyByConst(2))
+		}
+	}
+
+	var sample = func(state IntegrationState) float64 {
+		if byTime {
+			return state.Time
+		}
+		return state.Position.X
+	}
+
+	//the variable step integrator is stopped at the next row and the next change of the wind
+	var variableStep = isVariableStep(v.integrator)
+	var deltaTime = func(state IntegrationState) float64 {
+		if !variableStep {
+			if byTime {
+				return calculationTime
+			}
+			return calculationStep / state.Velocity.X
+		}
+		if byTime {
+			var windTime = (nextWindRange - state.Position.X) / state.Velocity.X
+			return math.Max(math.Min(nextRangeDistance-state.Time, windTime), calculationTime)
+		}
+		return math.Max(math.Min(nextRangeDistance-state.Position.X, nextWindRange-state.Position.X), calculationStep) / state.Velocity.X
+	}
 
 	//run all the way down the range
-	for rangeVector.X <= maximumRange+calculationStep {
-		if velocity < cMinimumVelocity || rangeVector.Y < cMaximumDrop {
+	for sample(state) <= maximumRange+calculationStep+calculationTime {
+		if velocity < minimumVelocity {
+			termination = TerminationMinimumVelocity
+			break
+		}
+		if state.Position.Y < maximumDrop {
+			termination = TerminationMaximumDrop
+			break
+		}
+		if v.hasGroundLevel && state.Position.Y < groundLevel && state.Velocity.Y < 0 {
+			termination = TerminationGroundHit
 			break
 		}
 
-		densityFactor, mach = atmosphere.getDensityFactorAndMachForAltitude(alt0 + rangeVector.Y)
-		//densityFactor = atmosphere.DensityFactor()
-		//mach = atmosphere.Mach().In(unit.Velocity_FPS)
+		_, mach = atmosphere.getDensityFactorAndMachForAltitude(alt0 + state.Position.Y)
 
-		if rangeVector.X >= nextWindRange {
+		if state.Position.X >= nextWindRange {
 			currentWind++
 			windVector = windToVector(shotInfo, windInfo[currentWind])
 
@@ -204,45 +527,121 @@ func (v TrajectoryCalculator) Trajectory(ammunition Ammunition, weapon Weapon, a
 			}
 		}
 
-		if rangeVector.X >= nextRangeDistance {
-			var windage = rangeVector.Z
-			if calculateDrift {
-				windage += (1.25 * (stabilityCoefficient + 1.2) * math.Pow(time, 1.83) * twistCoefficient) / 12.0
+		var windage = state.Position.Z
+		if calculateDrift {
+			windage += (1.25 * (stabilityCoefficient + 1.2) * math.Pow(state.Time, 1.83) * twistCoefficient) / 12.0
+		}
+
+		var coriolisVertical, coriolisHorizontal float64
+		if calculateCoriolis {
+			//compare with the trajectory without Coriolis effect at the same distance
+			var shift = state.Position.X - referenceState.Position.X
+			coriolisVertical = state.Position.Y - referenceState.Position.Y - shift*referenceState.Velocity.Y/referenceState.Velocity.X
+			coriolisHorizontal = state.Position.Z - referenceState.Position.Z - shift*referenceState.Velocity.Z/referenceState.Velocity.X
+		}
+
+		var jump = state.Position.X * math.Tan(aerodynamicJump)
+
+		var point = trajectoryPoint{
+			time:               state.Time,
+			distance:           state.Position.X,
+			drop:               state.Position.Y + jump,
+			windage:            windage,
+			velocity:           velocity,
+			mach:               velocity / mach,
+			coriolisHorizontal: coriolisHorizontal,
+			coriolisVertical:   coriolisVertical,
+			aerodynamicJump:    jump,
+		}
+
+		//the slope of the trajectory relative to the line of sight, the apex is where it turns negative
+		var slope = (state.Velocity.Y+state.Velocity.X*math.Tan(aerodynamicJump))/state.Velocity.X - lineOfSightSlope
+		if hasPrevious {
+			var event = func(kind byte, previousValue, value float64) {
+				events = append(events, TrajectoryEvent{
+					kind: kind,
+					data: previous.interpolate(point, previousValue/(previousValue-value)).toData(bulletWeight),
+				})
 			}
 
-			var dropAdjustment = getCorrection(rangeVector.X, rangeVector.Y)
-			var windageAdjustment = getCorrection(rangeVector.X, windage)
-
-			ranges[currentItem] = TrajectoryData{
-				time:              Timespan{time: time},
-				travelDistance:    unit.MustCreateDistance(rangeVector.X, unit.DistanceFoot),
-				drop:              unit.MustCreateDistance(rangeVector.Y, unit.DistanceFoot),
-				dropAdjustment:    unit.MustCreateAngular(dropAdjustment, unit.AngularRadian),
-				windage:           unit.MustCreateDistance(windage, unit.DistanceFoot),
-				windageAdjustment: unit.MustCreateAngular(windageAdjustment, unit.AngularRadian),
-				velocity:          unit.MustCreateVelocity(velocity, unit.VelocityFPS),
-				mach:              velocity / mach,
-				energy:            unit.MustCreateEnergy(calculateEnergy(bulletWeight, velocity), unit.EnergyFootPound),
-				optimalGameWeight: unit.MustCreateWeight(calculateOgv(bulletWeight, velocity), unit.WeightPound),
+			var previousHeight = previous.drop - previous.distance*lineOfSightSlope
+			var height = point.drop - point.distance*lineOfSightSlope
+			if previousHeight < 0 && height >= 0 {
+				event(EventNearZero, previousHeight, height)
+			}
+			if previousHeight > 0 && height <= 0 {
+				event(EventFarZero, previousHeight, height)
+			}
+			if previousSlope > 0 && slope <= 0 {
+				event(EventApex, previousSlope, slope)
+			}
+			if previous.mach >= 1.2 && point.mach < 1.2 {
+				event(EventTransonic, previous.mach-1.2, point.mach-1.2)
+			}
+			if previous.mach >= 1 && point.mach < 1 {
+				event(EventSubsonic, previous.mach-1, point.mach-1)
+			}
+			if v.hasEnergyThreshold {
+				var previousEnergy = calculateEnergy(bulletWeight, previous.velocity) - energyThreshold
+				var energy = calculateEnergy(bulletWeight, point.velocity) - energyThreshold
+				if previousEnergy >= 0 && energy < 0 {
+					event(EventEnergyThreshold, previousEnergy, energy)
+				}
+			}
+		}
+		previousSlope = slope
+
+		if hasPrevious && visit != nil && !visit(previous, point) {
+			break
+		}
+
+		//the rows are interpolated between the integration points exactly at the distance (or time) requested
+		for currentItem < len(ranges) && sample(state) >= nextRangeDistance {
+			var row = point
+			if hasPrevious {
+				var previousSample = previous.distance
+				if byTime {
+					previousSample = previous.time
+				}
+				row = previous.interpolate(point, (nextRangeDistance-previousSample)/(sample(state)-previousSample))
 			}
-			nextRangeDistance += step
+			ranges[currentItem] = row.toData(bulletWeight)
 			currentItem++
-			if currentItem == len(ranges) {
-				break
+			if distances != nil {
+				if currentItem < len(distances) {
+					nextRangeDistance = distances[currentItem]
+				}
+			} else {
+				nextRangeDistance = float64(currentItem) * step
 			}
 		}
+		previous, hasPrevious = point, true
+		if currentItem == len(ranges) {
+			break
+		}
 
-		deltaTime = calculationStep / velocityVector.X
-		velocityAdjusted = velocityVector.Subtract(windVector)
-		velocity = velocityAdjusted.Magnitude()
-		drag = ballisticFactor * densityFactor * velocity * bullet.BallisticCoefficient().Drag(velocity/mach)
-		velocityVector = velocityVector.Subtract((velocityAdjusted.MultiplyByConst(drag).Subtract(gravityVector)).MultiplyByConst(deltaTime))
-		deltaRangeVector = vector.Create(calculationStep, velocityVector.Y*deltaTime, velocityVector.Z*deltaTime)
-		rangeVector = rangeVector.Add(deltaRangeVector)
-		velocity = velocityVector.Magnitude()
-		time = time + deltaRangeVector.Magnitude()/velocity
+		var stepTime = deltaTime(state)
+		if calculateCoriolis && !variableStep {
+			referenceState = v.integrator.Step(referenceState, stepTime, referenceAcceleration)
+		}
+		state = v.integrator.Step(state, stepTime, acceleration)
+		//the variable step integrator may advance the trajectory without Coriolis effect by another time
+		for calculateCoriolis && variableStep && state.Time-referenceState.Time > stepTime*cAdaptiveMinimumStepFraction {
+			referenceState = v.integrator.Step(referenceState, state.Time-referenceState.Time, referenceAcceleration)
+		}
+		velocity = state.Velocity.Magnitude()
+	}
+	//several events may happen within one integration step
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].data.travelDistance.In(unit.DistanceFoot) < events[j].data.travelDistance.In(unit.DistanceFoot)
+	})
+	return TrajectoryResult{
+		data:         ranges[:currentItem],
+		termination:  termination,
+		lastDistance: previous.distance,
+		events:       events,
+		bulletWeight: bulletWeight,
 	}
-	return ranges
 }
 
 func calculateStabilityCoefficient(ammunitionInfo Ammunition, rifleInfo Weapon, atmosphere Atmosphere) float64 {
@@ -260,6 +659,24 @@ func calculateStabilityCoefficient(ammunitionInfo Ammunition, rifleInfo Weapon,
 	return sd * fv * ftp
 }
 
+//calculateAerodynamicJump calculates the vertical angle (in radians) at which the projectile is deflected
+//by the crosswind at the muzzle
+//
+//The calculation uses the empirical formula from Litz's "Applied Ballistics"
+func calculateAerodynamicJump(ammunitionInfo Ammunition, rifleInfo Weapon, wind WindInfo, stabilityCoefficient float64) float64 {
+	var length = ammunitionInfo.Bullet().BulletLength().In(unit.DistanceInch) / ammunitionInfo.Bullet().BulletDiameter().In(unit.DistanceInch)
+	var jumpPerMph = 0.01*stabilityCoefficient - 0.0024*length + 0.032
+	//positive crosswind is blowing from the left
+	var crosswind = wind.velocity.In(unit.VelocityMPH) * math.Sin(wind.direction.In(unit.AngularRadian))
+
+	//a right-hand twisted projectile is deflected up by the wind from the left
+	var jump = jumpPerMph * crosswind
+	if rifleInfo.Twist().Direction() == TwistLeft {
+		jump = -jump
+	}
+	return unit.MustCreateAngular(jump, unit.AngularMOA).In(unit.AngularRadian)
+}
+
 func windToVector(shot ShotParameters, wind WindInfo) vector.Vector {
 	var sightCosine = math.Cos(shot.SightAngle().In(unit.AngularRadian))
 	var sightSine = math.Sin(shot.SightAngle().In(unit.AngularRadian))
@@ -271,6 +688,17 @@ func windToVector(shot ShotParameters, wind WindInfo) vector.Vector {
 	return vector.Create(rangeVelocity*sightCosine, rangeFactor*cantCosine+crossComponent*cantSine, crossComponent*cantCosine-rangeFactor*cantSine)
 }
 
+//earthRotationVector returns the angular velocity of the Earth rotation in the coordinates
+//of the shot (x - towards the target, y - up, z - to the right)
+func earthRotationVector(latitude unit.Angular, azimuth unit.Angular) vector.Vector {
+	var latitudeRadians = latitude.In(unit.AngularRadian)
+	var azimuthRadians = azimuth.In(unit.AngularRadian)
+	return vector.Create(
+		cEarthAngularVelocity*math.Cos(latitudeRadians)*math.Cos(azimuthRadians),
+		cEarthAngularVelocity*math.Sin(latitudeRadians),
+		-cEarthAngularVelocity*math.Cos(latitudeRadians)*math.Sin(azimuthRadians))
+}
+
 func getCorrection(distance, offset float64) float64 {
 	return math.Atan(offset / distance)
 }
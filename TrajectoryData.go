@@ -28,16 +28,19 @@ func (v Timespan) Minutes() float64 {
 
 //TrajectoryData structure keeps information about one point of the trajectory.
 type TrajectoryData struct {
-	time              Timespan
-	travelDistance    unit.Distance
-	velocity          unit.Velocity
-	mach              float64
-	drop              unit.Distance
-	dropAdjustment    unit.Angular
-	windage           unit.Distance
-	windageAdjustment unit.Angular
-	energy            unit.Energy
-	optimalGameWeight unit.Weight
+	time               Timespan
+	travelDistance     unit.Distance
+	velocity           unit.Velocity
+	mach               float64
+	drop               unit.Distance
+	dropAdjustment     unit.Angular
+	windage            unit.Distance
+	windageAdjustment  unit.Angular
+	energy             unit.Energy
+	optimalGameWeight  unit.Weight
+	coriolisHorizontal unit.Distance
+	coriolisVertical   unit.Distance
+	aerodynamicJump    unit.Distance
 }
 
 //Time return the amount of time spent since the shot moment
@@ -96,3 +99,28 @@ func (v TrajectoryData) Energy() unit.Energy {
 func (v TrajectoryData) OptimalGameWeight() unit.Weight {
 	return v.optimalGameWeight
 }
+
+//CoriolisHorizontal returns the horizontal deflection of the projectile caused by the Coriolis effect
+//
+//The value is already included into Windage(). It is zero unless the Coriolis effect is set
+//in the shot parameters (see ShotParameters.SetCoriolis)
+func (v TrajectoryData) CoriolisHorizontal() unit.Distance {
+	return v.coriolisHorizontal
+}
+
+//CoriolisVertical returns the vertical deflection of the projectile caused by the Eötvös effect
+//
+//The value is already included into Drop(). It is zero unless the Coriolis effect is set
+//in the shot parameters (see ShotParameters.SetCoriolis)
+func (v TrajectoryData) CoriolisVertical() unit.Distance {
+	return v.coriolisVertical
+}
+
+//AerodynamicJump returns the vertical deflection of the projectile caused by the aerodynamic jump
+//in the crosswind
+//
+//The value is already included into Drop(). It is zero unless both the rifling twist and
+//the projectile dimensions are set.
+func (v TrajectoryData) AerodynamicJump() unit.Distance {
+	return v.aerodynamicJump
+}
@@ -0,0 +1,56 @@
+package externalballistics
+
+//EventNearZero is the event of the projectile crossing the line of sight upwards
+const EventNearZero byte = 1
+
+//EventFarZero is the event of the projectile crossing the line of sight downwards
+const EventFarZero byte = 2
+
+//EventApex is the event of the projectile reaching the maximum height over the line of sight (maximum ordinate)
+const EventApex byte = 3
+
+//EventTransonic is the event of the projectile velocity dropping below 1.2 mach
+const EventTransonic byte = 4
+
+//EventSubsonic is the event of the projectile velocity dropping below the speed of sound
+const EventSubsonic byte = 5
+
+//EventEnergyThreshold is the event of the projectile energy dropping below the threshold
+//set by TrajectoryCalculator.SetEnergyThreshold
+const EventEnergyThreshold byte = 6
+
+//TrajectoryEvent keeps the information about a special point of the trajectory
+type TrajectoryEvent struct {
+	kind byte
+	data TrajectoryData
+}
+
+//Kind returns the kind of the event (see Event* constants)
+func (v TrajectoryEvent) Kind() byte {
+	return v.kind
+}
+
+//Name returns the name of the event
+func (v TrajectoryEvent) Name() string {
+	switch v.kind {
+	case EventNearZero:
+		return "near zero"
+	case EventFarZero:
+		return "far zero"
+	case EventApex:
+		return "apex"
+	case EventTransonic:
+		return "transonic"
+	case EventSubsonic:
+		return "subsonic"
+	case EventEnergyThreshold:
+		return "energy threshold"
+	default:
+		return "unknown"
+	}
+}
+
+//Data returns the point of the trajectory at which the event happens
+func (v TrajectoryEvent) Data() TrajectoryData {
+	return v.data
+}
@@ -0,0 +1,149 @@
+package externalballistics
+
+import (
+	"fmt"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+)
+
+//TerminationMaximumRange is the termination reason indicating that the trajectory
+//is calculated up to the maximum distance (or time) of the shot
+const TerminationMaximumRange byte = 1
+
+//TerminationMinimumVelocity is the termination reason indicating that the calculation
+//is stopped because the projectile velocity dropped below the minimum velocity
+const TerminationMinimumVelocity byte = 2
+
+//TerminationMaximumDrop is the termination reason indicating that the calculation
+//is stopped because the projectile drop exceeded the maximum drop
+const TerminationMaximumDrop byte = 3
+
+//TerminationGroundHit is the termination reason indicating that the calculation
+//is stopped because the projectile hit the ground (see TrajectoryCalculator.SetGroundLevel)
+const TerminationGroundHit byte = 4
+
+//TrajectoryResult keeps the calculated trajectory and the reason why the calculation
+//of the trajectory ended
+type TrajectoryResult struct {
+	data         []TrajectoryData
+	termination  byte
+	events       []TrajectoryEvent
+	bulletWeight float64
+	lastDistance float64
+	trace        func(visit trajectoryVisitor)
+}
+
+//Data returns the calculated points of the trajectory
+//
+//If the calculation is terminated before the maximum distance of the shot is reached,
+//only the points calculated before the termination are returned.
+func (v TrajectoryResult) Data() []TrajectoryData {
+	return v.data
+}
+
+//Termination returns the reason why the calculation ended (see Termination* constants)
+func (v TrajectoryResult) Termination() byte {
+	return v.termination
+}
+
+//IsComplete returns the flag indicating whether the trajectory is calculated
+//up to the maximum distance (or time) of the shot
+func (v TrajectoryResult) IsComplete() bool {
+	return v.termination == TerminationMaximumRange
+}
+
+//Events returns the special points of the trajectory found during the calculation ordered by the distance
+//
+//The events are detected within the calculated part of the trajectory only.
+func (v TrajectoryResult) Events() []TrajectoryEvent {
+	return v.events
+}
+
+//AtDistance returns the point of the trajectory at the distance specified
+//
+//The point is interpolated between the integration points of the calculated trajectory, so the distance
+//doesn't have to be a multiple of the step of the shot. The method returns an error if the distance
+//is beyond the calculated part of the trajectory.
+//
+//The integration points aren't kept in the result, so the trajectory is calculated again
+//up to the distance requested on each call.
+func (v TrajectoryResult) AtDistance(distance unit.Distance) (TrajectoryData, error) {
+	var x = distance.In(unit.DistanceFoot)
+	if v.trace == nil || x < 0 {
+		return TrajectoryData{}, fmt.Errorf("TrajectoryResult: %w, the distance %s is out of the trajectory", ErrInvalidParameter, distance)
+	}
+	if x > v.lastDistance {
+		return TrajectoryData{}, fmt.Errorf("TrajectoryResult: %w, the trajectory is calculated up to %s only", ErrUnreachableTarget,
+			unit.MustCreateDistance(v.lastDistance, unit.DistanceFoot).Convert(distance.Units()))
+	}
+
+	var found bool
+	var result trajectoryPoint
+	v.trace(func(previous, point trajectoryPoint) bool {
+		if point.distance < x {
+			return true
+		}
+		result = point
+		if point.distance > x {
+			result = previous.interpolate(point, (x-previous.distance)/(point.distance-previous.distance))
+		}
+		found = true
+		return false
+	})
+	if !found {
+		return TrajectoryData{}, fmt.Errorf("TrajectoryResult: %w, the trajectory doesn't reach %s", ErrUnreachableTarget, distance)
+	}
+	return result.toData(v.bulletWeight), nil
+}
+
+//trajectoryPoint keeps the state of the projectile at one integration point
+//
+//The distances are in feet, the time is in seconds and the velocity is in feet per second.
+type trajectoryPoint struct {
+	time               float64
+	distance           float64
+	drop               float64
+	windage            float64
+	velocity           float64
+	mach               float64
+	coriolisHorizontal float64
+	coriolisVertical   float64
+	aerodynamicJump    float64
+}
+
+//interpolate returns the point at the fraction of the way between this and the next point
+func (v trajectoryPoint) interpolate(next trajectoryPoint, fraction float64) trajectoryPoint {
+	var lerp = func(a, b float64) float64 {
+		return a + (b-a)*fraction
+	}
+	return trajectoryPoint{
+		time:               lerp(v.time, next.time),
+		distance:           lerp(v.distance, next.distance),
+		drop:               lerp(v.drop, next.drop),
+		windage:            lerp(v.windage, next.windage),
+		velocity:           lerp(v.velocity, next.velocity),
+		mach:               lerp(v.mach, next.mach),
+		coriolisHorizontal: lerp(v.coriolisHorizontal, next.coriolisHorizontal),
+		coriolisVertical:   lerp(v.coriolisVertical, next.coriolisVertical),
+		aerodynamicJump:    lerp(v.aerodynamicJump, next.aerodynamicJump),
+	}
+}
+
+//toData creates the trajectory data for the point and the weight of the bullet (in grains)
+func (v trajectoryPoint) toData(bulletWeight float64) TrajectoryData {
+	return TrajectoryData{
+		time:               Timespan{time: v.time},
+		travelDistance:     unit.MustCreateDistance(v.distance, unit.DistanceFoot),
+		drop:               unit.MustCreateDistance(v.drop, unit.DistanceFoot),
+		dropAdjustment:     unit.MustCreateAngular(getCorrection(v.distance, v.drop), unit.AngularRadian),
+		windage:            unit.MustCreateDistance(v.windage, unit.DistanceFoot),
+		windageAdjustment:  unit.MustCreateAngular(getCorrection(v.distance, v.windage), unit.AngularRadian),
+		velocity:           unit.MustCreateVelocity(v.velocity, unit.VelocityFPS),
+		mach:               v.mach,
+		energy:             unit.MustCreateEnergy(calculateEnergy(bulletWeight, v.velocity), unit.EnergyFootPound),
+		optimalGameWeight:  unit.MustCreateWeight(calculateOgv(bulletWeight, v.velocity), unit.WeightPound),
+		coriolisHorizontal: unit.MustCreateDistance(v.coriolisHorizontal, unit.DistanceFoot),
+		coriolisVertical:   unit.MustCreateDistance(v.coriolisVertical, unit.DistanceFoot),
+		aerodynamicJump:    unit.MustCreateDistance(v.aerodynamicJump, unit.DistanceFoot),
+	}
+}
@@ -0,0 +1,186 @@
+package externalballistics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+)
+
+const cTruingAccuracy float64 = 0.001
+const cMaxTruingIterations int = 60
+const cMaxTruingScale float64 = 16
+
+//TruingResult keeps the convergence diagnostics of a truing calculation
+type TruingResult struct {
+	iterations int
+	residual   unit.Distance
+	converged  bool
+}
+
+//Iterations returns the number of trajectories calculated to find the solution
+func (v TruingResult) Iterations() int {
+	return v.iterations
+}
+
+//Residual returns the difference between the calculated and the observed drop
+//at the distance of the observation
+func (v TruingResult) Residual() unit.Distance {
+	return v.residual
+}
+
+//Converged returns the flag indicating whether the calculated drop matches
+//the observed drop with the required accuracy
+func (v TruingResult) Converged() bool {
+	return v.converged
+}
+
+//TrueBallisticCoefficient finds the ballistic coefficient with which the calculated trajectory
+//matches the drop observed at the distance specified
+//
+//The drop is measured the same way as TrajectoryData.Drop(), i.e. the negative value means that
+//the projectile is below the line of sight of the weapon zeroed as set in weapon.Zero().
+//
+//The ballistic coefficient of the projectile is scaled to find the solution, so the drag table
+//and the proportion between the multiple ballistic coefficient values stay the same. The form factor
+//is scaled inversely because the greater form factor means the greater drag.
+func (v TrajectoryCalculator) TrueBallisticCoefficient(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, distance unit.Distance, drop unit.Distance) (BallisticCoefficient, TruingResult, error) {
+	var ballisticCoefficient = ammunition.Bullet().BallisticCoefficient()
+	var scaled = func(scale float64) BallisticCoefficient {
+		if ballisticCoefficient.ValueType() == FF {
+			return ballisticCoefficient.scaled(1 / scale)
+		}
+		return ballisticCoefficient.scaled(scale)
+	}
+	var scale, result, _, err = v.trueByScale(distance, drop, func(scale float64) Ammunition {
+		var bullet = ammunition.Bullet()
+		bullet.ballisticCoefficient = scaled(scale)
+		return CreateAmmunition(bullet, ammunition.MuzzleVelocity())
+	}, weapon, atmosphere)
+	if err != nil {
+		return ballisticCoefficient, result, err
+	}
+	return scaled(scale), result, nil
+}
+
+//TrueBallisticCoefficientByAdjustment finds the ballistic coefficient with which the calculated trajectory
+//matches the drop adjustment observed at the distance specified
+//
+//The drop adjustment is measured the same way as TrajectoryData.DropAdjustment().
+func (v TrajectoryCalculator) TrueBallisticCoefficientByAdjustment(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, distance unit.Distance, dropAdjustment unit.Angular) (BallisticCoefficient, TruingResult, error) {
+	return v.TrueBallisticCoefficient(ammunition, weapon, atmosphere, distance, adjustmentToDrop(distance, dropAdjustment))
+}
+
+//TrueMuzzleVelocity finds the muzzle velocity with which the calculated trajectory
+//matches the drop observed at the distance specified
+//
+//The drop is measured the same way as TrajectoryData.Drop(), i.e. the negative value means that
+//the projectile is below the line of sight of the weapon zeroed as set in weapon.Zero().
+//
+//The distance must be within the supersonic range of the projectile, the method returns
+//an error wrapping ErrInvalidParameter otherwise.
+func (v TrajectoryCalculator) TrueMuzzleVelocity(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, distance unit.Distance, drop unit.Distance) (unit.Velocity, TruingResult, error) {
+	var muzzleVelocity = ammunition.MuzzleVelocity().In(unit.VelocityFPS)
+	var scale, result, mach, err = v.trueByScale(distance, drop, func(scale float64) Ammunition {
+		return CreateAmmunition(ammunition.Bullet(), unit.MustCreateVelocity(muzzleVelocity*scale, unit.VelocityFPS))
+	}, weapon, atmosphere)
+	if err != nil {
+		return ammunition.MuzzleVelocity(), result, err
+	}
+	if mach < 1 {
+		return ammunition.MuzzleVelocity(), result, fmt.Errorf("Truing: %w, the projectile is subsonic (%.2f mach) at the distance %s", ErrInvalidParameter, mach, distance)
+	}
+	return unit.MustCreateVelocity(muzzleVelocity*scale, unit.VelocityFPS).Convert(ammunition.MuzzleVelocity().Units()), result, nil
+}
+
+//TrueMuzzleVelocityByAdjustment finds the muzzle velocity with which the calculated trajectory
+//matches the drop adjustment observed at the distance specified
+//
+//The drop adjustment is measured the same way as TrajectoryData.DropAdjustment().
+func (v TrajectoryCalculator) TrueMuzzleVelocityByAdjustment(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, distance unit.Distance, dropAdjustment unit.Angular) (unit.Velocity, TruingResult, error) {
+	return v.TrueMuzzleVelocity(ammunition, weapon, atmosphere, distance, adjustmentToDrop(distance, dropAdjustment))
+}
+
+func adjustmentToDrop(distance unit.Distance, dropAdjustment unit.Angular) unit.Distance {
+	return unit.MustCreateDistance(distance.In(unit.DistanceFoot)*math.Tan(dropAdjustment.In(unit.AngularRadian)), unit.DistanceFoot)
+}
+
+//trueByScale finds the scale of the ammunition parameter at which the drop at the distance
+//matches the observed one
+//
+//The drop must grow (the projectile goes higher) when the scale grows. The method returns
+//the scale, the diagnostics and the mach at the distance for the scale found.
+func (v TrajectoryCalculator) trueByScale(distance unit.Distance, drop unit.Distance, ammunition func(float64) Ammunition, weapon Weapon, atmosphere Atmosphere) (float64, TruingResult, float64, error) {
+	var target = drop.In(unit.DistanceFoot)
+	var result TruingResult
+
+	var calculate = func(scale float64) (float64, float64, error) {
+		result.iterations++
+		var calculated, mach, err = v.dropAtDistance(ammunition(scale), weapon, atmosphere, distance)
+		return calculated - target, mach, err
+	}
+
+	var low, high = 1.0, 1.0
+	var residual, mach, err = calculate(1)
+	if err != nil {
+		return 1, result, 0, err
+	}
+	var lowResidual, highResidual = residual, residual
+
+	//find the range of the scale which contains the solution
+	for lowResidual > 0 {
+		low = low / 2
+		if low < 1/cMaxTruingScale {
+			return 1, result, 0, fmt.Errorf("Truing: %w, the observed drop %s is unreachable at the distance %s", ErrUnreachableTarget, drop, distance)
+		}
+		if lowResidual, _, err = calculate(low); err != nil {
+			return 1, result, 0, err
+		}
+	}
+	for highResidual < 0 {
+		high = high * 2
+		if high > cMaxTruingScale {
+			return 1, result, 0, fmt.Errorf("Truing: %w, the observed drop %s is unreachable at the distance %s", ErrUnreachableTarget, drop, distance)
+		}
+		if highResidual, _, err = calculate(high); err != nil {
+			return 1, result, 0, err
+		}
+	}
+
+	var scale = 1.0
+	for math.Abs(residual) > cTruingAccuracy && result.iterations < cMaxTruingIterations {
+		//false position method with the bisection fallback to keep the range shrinking
+		scale = low - lowResidual*(high-low)/(highResidual-lowResidual)
+		if scale <= low || scale >= high || result.iterations%2 == 0 {
+			scale = (low + high) / 2
+		}
+		if residual, mach, err = calculate(scale); err != nil {
+			return 1, result, 0, err
+		}
+		if residual < 0 {
+			low, lowResidual = scale, residual
+		} else {
+			high, highResidual = scale, residual
+		}
+	}
+
+	result.residual = unit.MustCreateDistance(residual, unit.DistanceFoot).Convert(drop.Units())
+	result.converged = math.Abs(residual) <= cTruingAccuracy
+	return scale, result, mach, nil
+}
+
+//dropAtDistance calculates the drop (in feet) and the mach of the projectile at the distance specified
+//
+//The truing doesn't converge if the weapon can't be zeroed with the ammunition tried.
+func (v TrajectoryCalculator) dropAtDistance(ammunition Ammunition, weapon Weapon, atmosphere Atmosphere, distance unit.Distance) (float64, float64, error) {
+	var sightAngle, err = v.SightAngleChecked(ammunition, weapon, atmosphere)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Truing: %w, the weapon can't be zeroed: %v", ErrNonConvergence, err)
+	}
+	var shotInfo = CreateShotParameters(sightAngle, distance, distance)
+	var data = v.Trajectory(ammunition, weapon, atmosphere, shotInfo, nil)
+	if len(data) < 2 || data[1].TravelledDistance().In(unit.DistanceFoot) < distance.In(unit.DistanceFoot)/2 {
+		return 0, 0, fmt.Errorf("Truing: %w, the projectile doesn't reach the distance %s", ErrUnreachableTarget, distance)
+	}
+	return data[1].Drop().In(unit.DistanceFoot), data[1].MachVelocity(), nil
+}
@@ -1,6 +1,11 @@
 package externalballistics
 
-import "github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+import (
+	"fmt"
+	"math"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+)
 
 //ZeroInfo structure keeps the information about zeroing of the weapon
 type ZeroInfo struct {
@@ -156,9 +161,51 @@ func CreateWeapon(sightHeight unit.Distance, zeroInfo ZeroInfo) Weapon {
 	return Weapon{sightHeight: sightHeight, zeroInfo: zeroInfo, hasTwistInfo: false}
 }
 
+//CreateWeaponChecked creates the weapon definition with no twist info the same way as CreateWeapon does,
+//but returns an error if the sight height or the zero distance is invalid
+func CreateWeaponChecked(sightHeight unit.Distance, zeroInfo ZeroInfo) (Weapon, error) {
+	if err := validateWeapon(sightHeight, zeroInfo); err != nil {
+		return Weapon{}, err
+	}
+	return CreateWeapon(sightHeight, zeroInfo), nil
+}
+
 //CreateWeaponWithTwist creates weapon description with twist info
 //
 //If twist info AND bullet dimensions are set, spin drift will be calculated
 func CreateWeaponWithTwist(sightHeight unit.Distance, zeroInfo ZeroInfo, twist TwistInfo) Weapon {
 	return Weapon{sightHeight: sightHeight, zeroInfo: zeroInfo, hasTwistInfo: true, twist: twist}
 }
+
+//CreateWeaponWithTwistChecked creates weapon description with twist info the same way as CreateWeaponWithTwist does,
+//but returns an error if the sight height, the zero distance or the twist is invalid
+func CreateWeaponWithTwistChecked(sightHeight unit.Distance, zeroInfo ZeroInfo, twist TwistInfo) (Weapon, error) {
+	if err := validateWeapon(sightHeight, zeroInfo); err != nil {
+		return Weapon{}, err
+	}
+	if twist.twistDirection != TwistRight && twist.twistDirection != TwistLeft {
+		return Weapon{}, fmt.Errorf("Weapon: %w, the twist direction must be either TwistRight or TwistLeft", ErrInvalidParameter)
+	}
+	if !isPositive(twist.riflingTwist.In(unit.DistanceInch)) {
+		return Weapon{}, fmt.Errorf("Weapon: %w, the rifling twist must be greater than zero", ErrInvalidParameter)
+	}
+	return CreateWeaponWithTwist(sightHeight, zeroInfo, twist), nil
+}
+
+func validateWeapon(sightHeight unit.Distance, zeroInfo ZeroInfo) error {
+	if !isFinite(sightHeight.In(unit.DistanceInch)) {
+		return fmt.Errorf("Weapon: %w, the sight height must be a finite number", ErrInvalidParameter)
+	}
+	if !isPositive(zeroInfo.ZeroDistance().In(unit.DistanceFoot)) {
+		return fmt.Errorf("Weapon: %w, the zero distance must be greater than zero", ErrInvalidParameter)
+	}
+	return nil
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+func isPositive(value float64) bool {
+	return value > 0 && !math.IsInf(value, 0)
+}
@@ -0,0 +1,57 @@
+package externalballistics
+
+import (
+	"fmt"
+
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+)
+
+//ZeroFindingNonConvergence is the reason of the zero finding error indicating that the
+//zero isn't found with the required accuracy within the maximum number of iterations
+const ZeroFindingNonConvergence byte = 1
+
+//ZeroFindingUnreachable is the reason of the zero finding error indicating that the projectile
+//can't reach the zero distance (it slows down below the minimum velocity or drops below the maximum drop)
+const ZeroFindingUnreachable byte = 2
+
+//ZeroFindingError is the error returned by TrajectoryCalculator.SightAngleChecked when the sight angle can't be found
+type ZeroFindingError struct {
+	reason     byte
+	residual   unit.Distance
+	iterations int
+}
+
+//Reason returns the reason of the error (see ZeroFinding* constants)
+func (v *ZeroFindingError) Reason() byte {
+	return v.reason
+}
+
+//Residual returns the residual of the last iteration
+//
+//For the non-convergence, it is the distance between the projectile and the line of sight
+//at the zero distance. For the unreachable zero, it is the distance the projectile falls short of
+//the zero distance.
+func (v *ZeroFindingError) Residual() unit.Distance {
+	return v.residual
+}
+
+//Iterations returns the number of iterations made
+func (v *ZeroFindingError) Iterations() int {
+	return v.iterations
+}
+
+//Unwrap returns ErrUnreachableTarget or ErrNonConvergence depending on the reason of the error
+func (v *ZeroFindingError) Unwrap() error {
+	if v.reason == ZeroFindingUnreachable {
+		return ErrUnreachableTarget
+	}
+	return ErrNonConvergence
+}
+
+//Error returns the description of the error
+func (v *ZeroFindingError) Error() string {
+	if v.reason == ZeroFindingUnreachable {
+		return fmt.Sprintf("ZeroFinding: the projectile falls %s short of the zero distance", v.residual)
+	}
+	return fmt.Sprintf("ZeroFinding: the zero isn't found after %d iterations, the residual is %s", v.iterations, v.residual)
+}
@@ -56,7 +56,7 @@ func angularToDefault(value float64, units byte) (float64, error) {
 	case AngularCmPer100M:
 		return math.Atan(value / 10000), nil
 	default:
-		return 0, fmt.Errorf("Angular: unit %d is not supported", units)
+		return 0, fmt.Errorf("Angular: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -79,7 +79,7 @@ func angularFromDefault(value float64, units byte) (float64, error) {
 	case AngularCmPer100M:
 		return math.Tan(value) * 10000, nil
 	default:
-		return 0, fmt.Errorf("Angular: unit %d is not supported", units)
+		return 0, fmt.Errorf("Angular: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -61,7 +61,7 @@ func distanceToDefault(value float64, units byte) (float64, error) {
 	case DistanceKilometer:
 		return value / 25.4 * 1000000, nil
 	default:
-		return 0, fmt.Errorf("Distance: unit %d is not supported", units)
+		return 0, fmt.Errorf("Distance: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -88,7 +88,7 @@ func distanceFromDefault(value float64, units byte) (float64, error) {
 	case DistanceKilometer:
 		return value * 25.4 / 1000000, nil
 	default:
-		return 0, fmt.Errorf("Distance: unit %d is not supported", units)
+		return 0, fmt.Errorf("Distance: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -15,7 +15,7 @@ func energyToDefault(value float64, units byte) (float64, error) {
 	case EnergyJoule:
 		return value * 0.737562149277, nil
 	default:
-		return 0, fmt.Errorf("Energy: unit %d is not supported", units)
+		return 0, fmt.Errorf("Energy: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -26,7 +26,7 @@ func energyFromDefault(value float64, units byte) (float64, error) {
 	case EnergyJoule:
 		return value / 0.737562149277, nil
 	default:
-		return 0, fmt.Errorf("Energy: unit %d is not supported", units)
+		return 0, fmt.Errorf("Energy: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -30,7 +30,7 @@ func pressureToDefault(value float64, units byte) (float64, error) {
 	case PressurePSI:
 		return value * 51.714924102396, nil
 	default:
-		return 0, fmt.Errorf("Pressure: unit %d is not supported", units)
+		return 0, fmt.Errorf("Pressure: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -47,7 +47,7 @@ func pressureFromDefault(value float64, units byte) (float64, error) {
 	case PressurePSI:
 		return value / 51.714924102396, nil
 	default:
-		return 0, fmt.Errorf("Pressure: unit %d is not supported", units)
+		return 0, fmt.Errorf("Pressure: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -25,7 +25,7 @@ func temperatureToDefault(value float64, units byte) (float64, error) {
 	case TemperatureKelvin:
 		return (value-273.15)*9/5 + 32, nil
 	default:
-		return 0, fmt.Errorf("Temperature: unit %d is not supported", units)
+		return 0, fmt.Errorf("Temperature: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -40,7 +40,7 @@ func temperatureFromDefault(value float64, units byte) (float64, error) {
 	case TemperatureKelvin:
 		return (value-32)*5/9 + 273.15, nil
 	default:
-		return 0, fmt.Errorf("Temperature: unit %d is not supported", units)
+		return 0, fmt.Errorf("Temperature: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -3,3 +3,10 @@
 //trajectory calculation - e.g. angular units, distance,
 //velocity, energy and so on.
 package unit
+
+import "errors"
+
+//ErrUnsupportedUnit is returned (wrapped) when the measurement unit isn't supported by the value
+//
+//Use errors.Is to check whether an error is caused by the unsupported unit.
+var ErrUnsupportedUnit = errors.New("unit is not supported")
@@ -1,6 +1,7 @@
 package unit_test
 
 import (
+	"errors"
 	"math"
 	"testing"
 
@@ -190,3 +191,30 @@ func TestWeight(t *testing.T) {
 	weightBackAndForth(t, 5, unit.WeightOunce)
 	weightBackAndForth(t, 6, unit.WeightPound)
 }
+
+func TestUnsupportedUnit(t *testing.T) {
+	var errs = []error{}
+	var err error
+	_, err = unit.CreateAngular(1, 255)
+	errs = append(errs, err)
+	_, err = unit.CreateDistance(1, 255)
+	errs = append(errs, err)
+	_, err = unit.CreateEnergy(1, 255)
+	errs = append(errs, err)
+	_, err = unit.CreatePressure(1, 255)
+	errs = append(errs, err)
+	_, err = unit.CreateTemperature(1, 255)
+	errs = append(errs, err)
+	_, err = unit.CreateVelocity(1, 255)
+	errs = append(errs, err)
+	_, err = unit.CreateWeight(1, 255)
+	errs = append(errs, err)
+	_, err = unit.MustCreateDistance(1, unit.DistanceFoot).Value(255)
+	errs = append(errs, err)
+
+	for i, err := range errs {
+		if !errors.Is(err, unit.ErrUnsupportedUnit) {
+			t.Errorf("Error %d: expected unsupported unit, got %v", i, err)
+		}
+	}
+}
@@ -30,7 +30,7 @@ func velocityToDefault(value float64, units byte) (float64, error) {
 	case VelocityKT:
 		return value / 1.94384449, nil
 	default:
-		return 0, fmt.Errorf("Velocity: unit %d is not supported", units)
+		return 0, fmt.Errorf("Velocity: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -47,7 +47,7 @@ func velocityFromDefault(value float64, units byte) (float64, error) {
 	case VelocityKT:
 		return value * 1.94384449, nil
 	default:
-		return 0, fmt.Errorf("Velocity: unit %d is not supported", units)
+		return 0, fmt.Errorf("Velocity: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
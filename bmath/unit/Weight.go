@@ -35,7 +35,7 @@ func weightToDefault(value float64, units byte) (float64, error) {
 	case WeightOunce:
 		return value * 437.5, nil
 	default:
-		return 0, fmt.Errorf("Weight: unit %d is not supported", units)
+		return 0, fmt.Errorf("Weight: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
@@ -54,7 +54,7 @@ func weightFromDefault(value float64, units byte) (float64, error) {
 	case WeightOunce:
 		return value / 437.5, nil
 	default:
-		return 0, fmt.Errorf("Weight: unit %d is not supported", units)
+		return 0, fmt.Errorf("Weight: %w (%d)", ErrUnsupportedUnit, units)
 	}
 }
 
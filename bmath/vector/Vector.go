@@ -33,7 +33,14 @@ func (v Vector) Copy() Vector {
 //
 //The product of two vectors is a sum of products of each coordinate
 func (v Vector) MultiplyByVector(b Vector) float64 {
-	return v.X*b.X + v.Y*v.Y + v.Z*b.Z
+	return v.X*b.X + v.Y*b.Y + v.Z*b.Z
+}
+
+//CrossProduct returns a cross product of two vectors
+//
+//The cross product is a vector which is perpendicular to both vectors
+func (v Vector) CrossProduct(b Vector) Vector {
+	return Create(v.Y*b.Z-v.Z*b.Y, v.Z*b.X-v.X*b.Z, v.X*b.Y-v.Y*b.X)
 }
 
 //Magnitude retruns a magnitude of the vector
@@ -64,8 +64,17 @@ func TestBinary(t *testing.T) {
 		t.Error("MultiplyByVector failed")
 	}
 
+	if v1.MultiplyByVector(vector.Create(4, 5, 6)) != (4 + 10 + 18) {
+		t.Error("MultiplyByVector of different vectors failed")
+	}
+
 	v2 = v1.MultiplyByConst(3)
 	if v2.X != 3 || v2.Y != 6 || v2.Z != 9 {
 		t.Error("MultiplyByConst failed")
 	}
+
+	v2 = v1.CrossProduct(vector.Create(4, 5, 6))
+	if v2.X != -3 || v2.Y != 6 || v2.Z != -3 {
+		t.Error("CrossProduct failed")
+	}
 }
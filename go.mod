@@ -1,3 +1,3 @@
 module github.com/gehtsoft-usa/go_ballisticcalc
 
-go 1.12
+go 1.13
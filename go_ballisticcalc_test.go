@@ -1,11 +1,20 @@
 package externalballistics_test
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	externalballistics "github.com/gehtsoft-usa/go_ballisticcalc"
 	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/unit"
+	"github.com/gehtsoft-usa/go_ballisticcalc/bmath/vector"
 )
 
 func TestZero1(t *testing.T) {
@@ -188,3 +197,1501 @@ func TestCustomCurve(t *testing.T) {
 	validateOneMetric(t, data[2], 200, -28.4, 544, 0.364)
 	validateOneMetric(t, data[15], 1500, -3627.8, 486, 2.892)
 }
+
+func TestZeroWithAnotherAmmoAndAtmosphere(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	zeroAmmo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2600, unit.VelocityFPS))
+	zeroAtmosphere, _ := externalballistics.CreateAtmosphere(unit.MustCreateDistance(0, unit.DistanceFoot),
+		unit.MustCreatePressure(29.92, unit.PressureInHg), unit.MustCreateTemperature(20, unit.TemperatureFahrenheit), 0.5)
+	atmosphere, _ := externalballistics.CreateAtmosphere(unit.MustCreateDistance(0, unit.DistanceFoot),
+		unit.MustCreatePressure(29.92, unit.PressureInHg), unit.MustCreateTemperature(90, unit.TemperatureFahrenheit), 0.5)
+	calc := externalballistics.CreateTrajectoryCalculator()
+
+	zero := externalballistics.CreateZeroInfoWithAnotherAmmoAndAtmosphere(unit.MustCreateDistance(100, unit.DistanceYard), zeroAmmo, zeroAtmosphere)
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	sameWeapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch),
+		externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard)))
+
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+	expected := calc.SightAngle(zeroAmmo, sameWeapon, zeroAtmosphere)
+	assertEqual(t, sightAngle.In(unit.AngularMOA), expected.In(unit.AngularMOA), 1e-9, "SightAngle")
+
+	shotInfo := externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(100, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard))
+	data := calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	if data[1].Drop().In(unit.DistanceInch) > -0.1 {
+		t.Errorf("Point of impact shift is expected, got %f", data[1].Drop().In(unit.DistanceInch))
+	}
+
+	zeroData := calc.Trajectory(zeroAmmo, weapon, zeroAtmosphere, shotInfo, nil)
+	assertEqual(t, zeroData[1].Drop().In(unit.DistanceInch), 0, 0.05, "Zero drop")
+}
+
+func TestIntegrators(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	wind := externalballistics.CreateOnlyWindInfo(unit.MustCreateVelocity(5, unit.VelocityMPH),
+		unit.MustCreateAngular(-45, unit.AngularDegree))
+
+	adaptive, err := externalballistics.CreateAdaptiveIntegrator(unit.MustCreateDistance(0.001, unit.DistanceInch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = externalballistics.CreateAdaptiveIntegrator(unit.MustCreateDistance(0, unit.DistanceInch))
+	if err == nil {
+		t.Error("Zero tolerance must be rejected")
+	}
+
+	calc := externalballistics.CreateTrajectoryCalculator()
+	shotInfo := externalballistics.CreateShotParameters(calc.SightAngle(ammo, weapon, atmosphere),
+		unit.MustCreateDistance(1000, unit.DistanceYard),
+		unit.MustCreateDistance(100, unit.DistanceYard))
+	euler := calc.Trajectory(ammo, weapon, atmosphere, shotInfo, wind)
+
+	calc.SetMaximumCalculatorStepSize(unit.MustCreateDistance(10, unit.DistanceFoot))
+	calc.SetIntegrator(externalballistics.CreateRungeKuttaIntegrator())
+	rk4 := calc.Trajectory(ammo, weapon, atmosphere, shotInfo, wind)
+
+	calc.SetIntegrator(adaptive)
+	rk45 := calc.Trajectory(ammo, weapon, atmosphere, shotInfo, wind)
+
+	assertEqual(t, float64(len(rk4)), 11, 0.1, "Length")
+	assertEqual(t, float64(len(rk45)), 11, 0.1, "Length")
+	for i := 1; i < len(euler); i++ {
+		var distance = rk45[i].TravelledDistance().In(unit.DistanceYard)
+		assertEqual(t, rk4[i].TravelledDistance().In(unit.DistanceYard), distance, 0.01, "RK4 Distance")
+		assertEqual(t, rk4[i].Drop().In(unit.DistanceInch)/distance, rk45[i].Drop().In(unit.DistanceInch)/distance, 0.001, "RK4 Drop")
+		assertEqual(t, rk4[i].Windage().In(unit.DistanceInch)/distance, rk45[i].Windage().In(unit.DistanceInch)/distance, 0.001, "RK4 Windage")
+		assertEqual(t, rk4[i].Time().TotalSeconds(), rk45[i].Time().TotalSeconds(), 0.001, "RK4 Time")
+		assertEqual(t, euler[i].DropAdjustment().In(unit.AngularMOA), rk45[i].DropAdjustment().In(unit.AngularMOA), 0.1, "Euler Drop")
+		assertEqual(t, euler[i].Velocity().In(unit.VelocityFPS), rk45[i].Velocity().In(unit.VelocityFPS), 2, "Euler Velocity")
+	}
+
+	//the adaptive integrator chooses the step itself, so the maximum calculator step size doesn't matter
+	calc.SetMaximumCalculatorStepSize(unit.MustCreateDistance(1, unit.DistanceFoot))
+	fine := calc.Trajectory(ammo, weapon, atmosphere, shotInfo, wind)
+	for i := 1; i < len(fine); i++ {
+		assertEqual(t, fine[i].Drop().In(unit.DistanceInch), rk45[i].Drop().In(unit.DistanceInch), 0.01, "Adaptive Drop")
+		assertEqual(t, fine[i].Windage().In(unit.DistanceInch), rk45[i].Windage().In(unit.DistanceInch), 0.01, "Adaptive Windage")
+	}
+
+	//the trajectory of the high angle shot is strongly curved, so the adaptive step must follow the curvature
+	highAngle := externalballistics.CreateShotParameters(unit.MustCreateAngular(45, unit.AngularDegree),
+		unit.MustCreateDistance(1500, unit.DistanceYard),
+		unit.MustCreateDistance(250, unit.DistanceYard))
+	steep := calc.Trajectory(ammo, weapon, atmosphere, highAngle, wind)
+	calc.SetIntegrator(externalballistics.CreateRungeKuttaIntegrator())
+	reference := calc.Trajectory(ammo, weapon, atmosphere, highAngle, wind)
+	assertEqual(t, float64(len(steep)), float64(len(reference)), 0.1, "High angle Length")
+	for i := 1; i < len(reference); i++ {
+		assertEqual(t, steep[i].Drop().In(unit.DistanceInch), reference[i].Drop().In(unit.DistanceInch), 0.1, "High angle Drop")
+		assertEqual(t, steep[i].Windage().In(unit.DistanceInch), reference[i].Windage().In(unit.DistanceInch), 0.1, "High angle Windage")
+		assertEqual(t, steep[i].Time().TotalSeconds(), reference[i].Time().TotalSeconds(), 0.0001, "High angle Time")
+	}
+}
+
+//adaptiveWrapper is an integrator defined outside the package which chooses the size of the step itself
+type adaptiveWrapper struct {
+	externalballistics.Integrator
+}
+
+func (v adaptiveWrapper) IsVariableStep() bool {
+	return true
+}
+
+func TestVariableStepIntegrator(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch),
+		externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard)))
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	adaptive, _ := externalballistics.CreateAdaptiveIntegrator(unit.MustCreateDistance(0.001, unit.DistanceInch))
+	if _, ok := adaptive.(externalballistics.VariableStepIntegrator); !ok {
+		t.Fatal("VariableStepIntegrator: the adaptive integrator must choose the step itself")
+	}
+
+	calc := externalballistics.CreateTrajectoryCalculator()
+	calc.SetIntegrator(adaptive)
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+	shotInfo := externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard))
+	expected := calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)
+
+	//the calculator treats the integrator of the user the same way as the built-in one
+	calc.SetIntegrator(adaptiveWrapper{adaptive})
+	assertEqual(t, calc.SightAngle(ammo, weapon, atmosphere).In(unit.AngularMOA), sightAngle.In(unit.AngularMOA), 1e-9, "Sight angle")
+	data := calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	assertEqual(t, float64(len(data)), float64(len(expected)), 0.1, "Length")
+	for i := range data {
+		assertEqual(t, data[i].Drop().In(unit.DistanceInch), expected[i].Drop().In(unit.DistanceInch), 1e-9, "Drop")
+		assertEqual(t, data[i].Time().TotalSeconds(), expected[i].Time().TotalSeconds(), 1e-9, "Time")
+	}
+}
+
+func TestAdaptiveIntegratorStep(t *testing.T) {
+	adaptive, _ := externalballistics.CreateAdaptiveIntegrator(unit.MustCreateDistance(0.001, unit.DistanceInch))
+	var drag = func(position vector.Vector, velocity vector.Vector) vector.Vector {
+		return velocity.MultiplyByConst(-1.4e-4 * velocity.Magnitude())
+	}
+	var steps = func(velocity vector.Vector, acceleration externalballistics.AccelerationFunction) []float64 {
+		var state = externalballistics.IntegrationState{Velocity: velocity}
+		var steps []float64
+		for i := 0; i < 20; i++ {
+			var next = adaptive.Step(state, 1, acceleration)
+			if next.Time <= state.Time || next.Time-state.Time > 1+1e-12 {
+				t.Fatalf("Step: the step %g must be within (0, 1] seconds", next.Time-state.Time)
+			}
+			steps = append(steps, next.Time-state.Time)
+			state = next
+		}
+		return steps
+	}
+
+	//the step accepted is kept between the calls, so it grows while the projectile slows down
+	var straight = steps(vector.Create(2700, 0, 0), drag)
+	if straight[len(straight)-1] <= 2*straight[1] {
+		t.Errorf("Step: the step must grow, got %g after %g", straight[len(straight)-1], straight[1])
+	}
+	//the drag along the velocity doesn't bend the trajectory whatever the direction of the shot is
+	var steep = steps(vector.Create(2700*math.Cos(math.Pi/3), 2700*math.Sin(math.Pi/3), 0), drag)
+	for i := range steep {
+		assertEqual(t, steep[i], straight[i], straight[i]*1e-6, "Step at a high angle")
+	}
+
+	//the gravity bends the trajectory, so the step is limited to keep the linear interpolation accurate
+	var gravity = 32.17
+	var curved = steps(vector.Create(2700, 0, 0), func(position vector.Vector, velocity vector.Vector) vector.Vector {
+		return drag(position, velocity).Add(vector.Create(0, -gravity, 0))
+	})
+	for _, step := range curved {
+		if step > math.Sqrt(8*unit.MustCreateDistance(0.001, unit.DistanceInch).In(unit.DistanceFoot)/gravity)*1.01 {
+			t.Errorf("Step: the step %g is too large for the linear interpolation", step)
+		}
+	}
+
+	//the step must end even if the error can't be estimated
+	var done = make(chan externalballistics.IntegrationState, 1)
+	go func() {
+		done <- adaptive.Step(externalballistics.IntegrationState{Velocity: vector.Create(2700, 0, 0)}, 0.01,
+			func(position vector.Vector, velocity vector.Vector) vector.Vector {
+				return vector.Create(math.NaN(), 0, 0)
+			})
+	}()
+	select {
+	case next := <-done:
+		if next.Time <= 0 {
+			t.Errorf("Step: the state must be advanced, got %g", next.Time)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Step: the step doesn't end when the error is not a number")
+	}
+}
+
+func TestTrajectoryByTime(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+
+	shotInfo := externalballistics.CreateShotParametersByTime(sightAngle, time.Second, 100*time.Millisecond)
+	data := calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	assertEqual(t, float64(len(data)), 11, 0.1, "Length")
+
+	reference := calc.Trajectory(ammo, weapon, atmosphere,
+		externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(1, unit.DistanceFoot)),
+		nil)
+
+	for i, point := range data {
+		assertEqual(t, point.Time().TotalSeconds(), float64(i)*0.1, 0.0001, "Time")
+		var feet = point.TravelledDistance().In(unit.DistanceFoot)
+		var other = reference[int(math.Round(feet))]
+		var shift = feet - other.TravelledDistance().In(unit.DistanceFoot)
+		assertEqual(t, point.Time().TotalSeconds(), other.Time().TotalSeconds()+shift/other.Velocity().In(unit.VelocityFPS), 0.0005, "Time at distance")
+		assertEqual(t, point.Drop().In(unit.DistanceInch), other.Drop().In(unit.DistanceInch), 0.25, "Drop at distance")
+		assertEqual(t, point.Velocity().In(unit.VelocityFPS), other.Velocity().In(unit.VelocityFPS), 1, "Velocity at distance")
+	}
+
+	//the maximum time which isn't an exact binary fraction of the step must still produce the last row
+	for _, maximum := range []time.Duration{300 * time.Millisecond, 700 * time.Millisecond} {
+		data = calc.Trajectory(ammo, weapon, atmosphere, externalballistics.CreateShotParametersByTime(sightAngle, maximum, 100*time.Millisecond), nil)
+		assertEqual(t, float64(len(data)), float64(maximum/(100*time.Millisecond))+1, 0.1, "Length")
+		assertEqual(t, data[len(data)-1].Time().TotalSeconds(), maximum.Seconds(), 0.0001, "Last time")
+	}
+}
+
+func TestCoriolis(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+
+	calculate := func(latitude, azimuth float64) externalballistics.TrajectoryData {
+		shotInfo := externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(1000, unit.DistanceYard))
+		shotInfo.SetCoriolis(unit.MustCreateAngular(latitude, unit.AngularDegree), unit.MustCreateAngular(azimuth, unit.AngularDegree))
+		return calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)[1]
+	}
+
+	plain := calc.Trajectory(ammo, weapon, atmosphere,
+		externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(1000, unit.DistanceYard)),
+		nil)[1]
+	assertEqual(t, plain.CoriolisHorizontal().In(unit.DistanceInch), 0, 1e-10, "No Coriolis")
+
+	//for a flat shot horizontal deflection is 2·ω·sin(latitude)·(X·t - ∫X·dt)
+	byTime := externalballistics.CreateShotParametersByTime(sightAngle, 1500*time.Millisecond, 10*time.Millisecond)
+	byTime.SetCoriolis(unit.MustCreateAngular(45, unit.AngularDegree), unit.MustCreateAngular(0, unit.AngularDegree))
+	data := calc.Trajectory(ammo, weapon, atmosphere, byTime, nil)
+	var integral float64
+	for i := 1; i < len(data); i++ {
+		integral += (data[i].TravelledDistance().In(unit.DistanceInch) + data[i-1].TravelledDistance().In(unit.DistanceInch)) / 2 *
+			(data[i].Time().TotalSeconds() - data[i-1].Time().TotalSeconds())
+	}
+	var last = data[len(data)-1]
+	var expected = 2 * 7.292115e-5 * math.Sin(math.Pi/4) * (last.TravelledDistance().In(unit.DistanceInch)*last.Time().TotalSeconds() - integral)
+	assertEqual(t, last.CoriolisHorizontal().In(unit.DistanceInch), expected, 0.02, "Horizontal deflection")
+
+	north := calculate(45, 0)
+	expected = north.CoriolisHorizontal().In(unit.DistanceInch)
+	if expected < 2 || expected > 3.5 {
+		t.Errorf("Northern hemisphere failed %f", expected)
+	}
+	assertEqual(t, north.Windage().In(unit.DistanceInch), north.CoriolisHorizontal().In(unit.DistanceInch), 0.01, "Windage")
+	south := calculate(-45, 0)
+	assertEqual(t, south.CoriolisHorizontal().In(unit.DistanceInch), -expected, 0.1, "Southern hemisphere")
+
+	//Eötvös effect raises the projectile shot to the east and lowers the projectile shot to the west
+	east := calculate(0, 90)
+	west := calculate(0, 270)
+	if east.CoriolisVertical().In(unit.DistanceInch) < 0.5 || west.CoriolisVertical().In(unit.DistanceInch) > -0.5 {
+		t.Errorf("Eötvös effect failed %f/%f", east.CoriolisVertical().In(unit.DistanceInch), west.CoriolisVertical().In(unit.DistanceInch))
+	}
+	assertEqual(t, east.Drop().In(unit.DistanceInch)-plain.Drop().In(unit.DistanceInch), east.CoriolisVertical().In(unit.DistanceInch), 0.01, "Drop")
+	assertEqual(t, calculate(0, 0).CoriolisVertical().In(unit.DistanceInch), 0, 0.01, "Equator to the north")
+}
+
+func TestAerodynamicJump(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectileWithDimensions(bc, unit.MustCreateDistance(0.308, unit.DistanceInch),
+		unit.MustCreateDistance(1.282, unit.DistanceInch), unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	shotInfo := externalballistics.CreateShotParameters(unit.MustCreateAngular(4.221, unit.AngularMOA),
+		unit.MustCreateDistance(1000, unit.DistanceYard),
+		unit.MustCreateDistance(500, unit.DistanceYard))
+
+	calculate := func(direction byte, windDirection float64) externalballistics.TrajectoryData {
+		twist := externalballistics.CreateTwist(direction, unit.MustCreateDistance(11.24, unit.DistanceInch))
+		weapon := externalballistics.CreateWeaponWithTwist(unit.MustCreateDistance(2, unit.DistanceInch), zero, twist)
+		wind := externalballistics.CreateOnlyWindInfo(unit.MustCreateVelocity(10, unit.VelocityMPH),
+			unit.MustCreateAngular(windDirection, unit.AngularDegree))
+		return calc.Trajectory(ammo, weapon, atmosphere, shotInfo, wind)[2]
+	}
+
+	var fromLeft = calculate(externalballistics.TwistRight, 90)
+	var fromRight = calculate(externalballistics.TwistRight, -90)
+	var jump = fromLeft.AerodynamicJump().In(unit.DistanceInch)
+	//about 0.04 MOA per 1 mph of crosswind for a typical .308 match bullet
+	if jump < 3 || jump > 6 {
+		t.Errorf("Aerodynamic jump is out of range %f", jump)
+	}
+	assertEqual(t, fromRight.AerodynamicJump().In(unit.DistanceInch), -jump, 1e-7, "Wind from the right")
+	assertEqual(t, calculate(externalballistics.TwistLeft, 90).AerodynamicJump().In(unit.DistanceInch), -jump, 1e-7, "Left twist")
+	assertEqual(t, calculate(externalballistics.TwistRight, 0).AerodynamicJump().In(unit.DistanceInch), 0, 1e-7, "Headwind")
+	assertEqual(t, fromLeft.Drop().In(unit.DistanceInch)-fromRight.Drop().In(unit.DistanceInch), 2*jump, 1e-7, "Drop")
+}
+
+func TestMultipleBallisticCoefficient(t *testing.T) {
+	_, err := externalballistics.CreateMultipleBallisticCoefficient(externalballistics.DragTableG1)
+	if err == nil {
+		t.Error("Empty list of values must be rejected")
+	}
+	_, err = externalballistics.CreateMultipleBallisticCoefficient(externalballistics.DragTableG1,
+		externalballistics.CreateBallisticCoefficientPoint(0.475, unit.MustCreateVelocity(2800, unit.VelocityFPS)),
+		externalballistics.CreateBallisticCoefficientPoint(-0.46, unit.MustCreateVelocity(2200, unit.VelocityFPS)))
+	if err == nil {
+		t.Error("Negative value must be rejected")
+	}
+
+	multiBC, err := externalballistics.CreateMultipleBallisticCoefficient(externalballistics.DragTableG1,
+		externalballistics.CreateBallisticCoefficientPoint(0.440, unit.MustCreateVelocity(1800, unit.VelocityFPS)),
+		externalballistics.CreateBallisticCoefficientPoint(0.475, unit.MustCreateVelocity(2800, unit.VelocityFPS)),
+		externalballistics.CreateBallisticCoefficientPoint(0.460, unit.MustCreateVelocity(2200, unit.VelocityFPS)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !multiBC.HasMultipleValues() || len(multiBC.MultipleValues()) != 3 {
+		t.Fatal("Multiple values aren't set")
+	}
+	assertEqual(t, multiBC.Value(), 0.475, 1e-10, "Value")
+	assertEqual(t, multiBC.MultipleValues()[1].Velocity().In(unit.VelocityFPS), 2200, 1e-10, "Order")
+
+	//the values returned must not change the ballistic coefficient
+	multiBC.MultipleValues()[0] = externalballistics.CreateBallisticCoefficientPoint(0.1, unit.MustCreateVelocity(2800, unit.VelocityFPS))
+	assertEqual(t, multiBC.MultipleValues()[0].Value(), 0.475, 1e-10, "Value after change")
+
+	highBC, _ := externalballistics.CreateBallisticCoefficient(0.475, externalballistics.DragTableG1)
+	lowBC, _ := externalballistics.CreateBallisticCoefficient(0.440, externalballistics.DragTableG1)
+	singleBC, _ := externalballistics.CreateMultipleBallisticCoefficient(externalballistics.DragTableG1,
+		externalballistics.CreateBallisticCoefficientPoint(0.475, unit.MustCreateVelocity(2800, unit.VelocityFPS)))
+
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	calculate := func(bc externalballistics.BallisticCoefficient) externalballistics.TrajectoryData {
+		projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+		ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+		shotInfo := externalballistics.CreateShotParameters(calc.SightAngle(ammo, weapon, atmosphere),
+			unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(1000, unit.DistanceYard))
+		return calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)[1]
+	}
+
+	var high = calculate(highBC)
+	var low = calculate(lowBC)
+	var multi = calculate(multiBC)
+	assertEqual(t, calculate(singleBC).Drop().In(unit.DistanceInch), high.Drop().In(unit.DistanceInch), 1e-7, "Single value")
+	if !(multi.Drop().In(unit.DistanceInch) < high.Drop().In(unit.DistanceInch) && multi.Drop().In(unit.DistanceInch) > low.Drop().In(unit.DistanceInch)) {
+		t.Errorf("Drop with multiple BC %f must be between %f and %f", multi.Drop().In(unit.DistanceInch), low.Drop().In(unit.DistanceInch), high.Drop().In(unit.DistanceInch))
+	}
+	if !(multi.Velocity().In(unit.VelocityFPS) < high.Velocity().In(unit.VelocityFPS) && multi.Velocity().In(unit.VelocityFPS) > low.Velocity().In(unit.VelocityFPS)) {
+		t.Errorf("Velocity with multiple BC %f must be between %f and %f", multi.Velocity().In(unit.VelocityFPS), low.Velocity().In(unit.VelocityFPS), high.Velocity().In(unit.VelocityFPS))
+	}
+}
+
+func TestCustomDragTable(t *testing.T) {
+	_, err := externalballistics.CreateBallisticCoefficientForCustomDragTable(1, externalballistics.FF, customTable[:1])
+	if err == nil {
+		t.Error("Table with one point must be rejected")
+	}
+	_, err = externalballistics.CreateBallisticCoefficientForCustomDragTable(1, externalballistics.FF,
+		[]externalballistics.DataPoint{{A: 0, B: 0.119}, {A: 0.9, B: 0.126}, {A: 0.85, B: 0.12}})
+	if err == nil {
+		t.Error("Unordered table must be rejected")
+	}
+
+	bc, err := externalballistics.CreateBallisticCoefficientForCustomDragTable(1, externalballistics.FF, customTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reference, _ := externalballistics.CreateBallisticCoefficientForCustomDragFunction(1, externalballistics.FF, customDragFunction)
+	for mach := 0.0; mach < 1.2; mach += 0.05 {
+		assertEqual(t, bc.Drag(mach), reference.Drag(mach), 1e-12, "Drag")
+	}
+
+	var projectile = externalballistics.CreateProjectileWithDimensions(bc, unit.MustCreateDistance(119.56, unit.DistanceMillimeter), unit.MustCreateDistance(20, unit.DistanceInch), unit.MustCreateWeight(13585, unit.WeightGram))
+	var ammo = externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(555, unit.VelocityMPS))
+	var zero = externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceMeter))
+	var weapon = externalballistics.CreateWeapon(unit.MustCreateDistance(40, unit.DistanceMillimeter), zero)
+	var atmosphere = externalballistics.CreateDefaultAtmosphere()
+
+	var calc = externalballistics.CreateTrajectoryCalculator()
+	var sightAngle = calc.SightAngle(ammo, weapon, atmosphere)
+	var shotInfo = externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1500, unit.DistanceMeter), unit.MustCreateDistance(100, unit.DistanceMeter))
+	var data = calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	validateOneMetric(t, data[1], 100, 0, 550, 0.182)
+	validateOneMetric(t, data[15], 1500, -3627.8, 486, 2.892)
+}
+
+func TestLoadDragTableCSV(t *testing.T) {
+	var data = "# radar data\nMach,Cd\n0.0,0.119\n0.7,0.119\n0.85,0.12\n0.87,0.122\n0.9,0.126\n0.93,0.148\n0.95,0.182\n"
+	table, err := externalballistics.LoadDragTableCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, float64(len(table)), float64(len(customTable)), 0.1, "Length")
+	for i := range table {
+		assertEqual(t, table[i].A, customTable[i].A, 1e-10, "Mach")
+		assertEqual(t, table[i].B, customTable[i].B, 1e-10, "Cd")
+	}
+
+	table, err = externalballistics.LoadDragTableCSV(strings.NewReader("0.5,0.2\n1.0,0.4\n"))
+	if err != nil || len(table) != 2 || table[1].A != 1.0 || table[1].B != 0.4 {
+		t.Errorf("Table without header failed %v", err)
+	}
+
+	table, err = externalballistics.LoadDragTableCSV(strings.NewReader("index,Velocity (m/s),Drag Coefficient\n1,170.1,0.2\n2,340.2,0.4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, table[1].A, 340.2/340.3, 0.001, "Velocity in m/s")
+	assertEqual(t, table[1].B, 0.4, 1e-10, "Drag after velocity")
+
+	table, err = externalballistics.LoadDragTableCSV(strings.NewReader("cd,velocity\n0.2,558\n0.4,1116\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, table[1].A, 1, 0.001, "Velocity in fps")
+
+	var malformed = []string{
+		"",
+		"mach,cd\n0.5,0.2\n",
+		"mach,cd\n0.5,0.2\n0.4,0.3\n",
+		"mach,cd\n0.5,0.2\n0.6,abc\n",
+		"mach,cd\n0.5,0.2\n0.6\n",
+		"speed,weight\n0.5,0.2\n0.6,0.3\n",
+		"mach,cd\n0.5,-0.2\n0.6,0.3\n",
+	}
+	for _, text := range malformed {
+		if _, err = externalballistics.LoadDragTableCSV(strings.NewReader(text)); err == nil {
+			t.Errorf("Malformed table %q must be rejected", text)
+		}
+	}
+}
+
+func TestLoadDragTableJSON(t *testing.T) {
+	table, err := externalballistics.LoadDragTableJSON(strings.NewReader(`[{"mach": 0.5, "cd": 0.2}, {"Mach": 1.0, "Cd": 0.4}]`))
+	if err != nil || len(table) != 2 || table[1].A != 1.0 || table[1].B != 0.4 {
+		t.Errorf("Table of objects failed %v", err)
+	}
+	table, err = externalballistics.LoadDragTableJSON(strings.NewReader(`[[0.5, 0.2], [1.0, 0.4]]`))
+	if err != nil || len(table) != 2 || table[1].A != 1.0 || table[1].B != 0.4 {
+		t.Errorf("Table of pairs failed %v", err)
+	}
+	table, err = externalballistics.LoadDragTableJSON(strings.NewReader(`[{"velocity_mps": 170.1, "cd": 0.2}, {"velocity_mps": 340.2, "cd": 0.4}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, table[1].A, 1, 0.001, "Velocity in m/s")
+
+	var malformed = []string{
+		`{"mach": 0.5, "cd": 0.2}`,
+		`[[0.5, 0.2, 0.1], [1.0, 0.4]]`,
+		`[{"mach": 0.5}, {"mach": 1.0, "cd": 0.4}]`,
+		`[{"mach": 0.5, "cd": 0.2}, {"mach": 0.4, "cd": 0.4}]`,
+		`[{"mach": 0.5, "cd": "x"}, {"mach": 1.0, "cd": 0.4}]`,
+	}
+	for _, text := range malformed {
+		if _, err = externalballistics.LoadDragTableJSON(strings.NewReader(text)); err == nil {
+			t.Errorf("Malformed table %q must be rejected", text)
+		}
+	}
+}
+
+func TestLoadDragTableFile(t *testing.T) {
+	directory, err := ioutil.TempDir("", "dragtable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	var fileName = filepath.Join(directory, "table.csv")
+	if err = ioutil.WriteFile(fileName, []byte("mach,cd\n0.5,0.2\n1.0,0.4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := externalballistics.LoadDragTableFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc, err := externalballistics.CreateBallisticCoefficientForCustomDragTable(0.5, externalballistics.BC, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, bc.Drag(1.0)/bc.Drag(0.5), 2, 1e-7, "Drag")
+
+	if _, err = externalballistics.LoadDragTableFile(filepath.Join(directory, "table.txt")); err == nil {
+		t.Error("Unknown file extension must be rejected")
+	}
+	if _, err = externalballistics.LoadDragTableFile(filepath.Join(directory, "missing.json")); err == nil {
+		t.Error("Missing file must be rejected")
+	}
+}
+
+func TestTrueBallisticCoefficient(t *testing.T) {
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	distance := unit.MustCreateDistance(800, unit.DistanceYard)
+
+	actualBC, _ := externalballistics.CreateBallisticCoefficient(0.243, externalballistics.DragTableG7)
+	actual := externalballistics.CreateAmmunition(externalballistics.CreateProjectile(actualBC, unit.MustCreateWeight(168, unit.WeightGrain)),
+		unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	shotInfo := externalballistics.CreateShotParameters(calc.SightAngle(actual, weapon, atmosphere), distance, distance)
+	observed := calc.Trajectory(actual, weapon, atmosphere, shotInfo, nil)[1]
+
+	guessBC, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	guess := externalballistics.CreateAmmunition(externalballistics.CreateProjectile(guessBC, unit.MustCreateWeight(168, unit.WeightGrain)),
+		unit.MustCreateVelocity(2750, unit.VelocityFPS))
+
+	bc, result, err := calc.TrueBallisticCoefficient(guess, weapon, atmosphere, distance, observed.Drop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Converged() || result.Iterations() < 2 {
+		t.Errorf("Truing didn't converge: %d iterations, residual %s", result.Iterations(), result.Residual())
+	}
+	assertEqual(t, bc.Value(), 0.243, 0.0005, "BC")
+	assertEqual(t, result.Residual().In(unit.DistanceInch), 0, 0.05, "Residual")
+	if bc.Table() != externalballistics.DragTableG7 {
+		t.Error("Drag table must not be changed")
+	}
+
+	bc, _, err = calc.TrueBallisticCoefficientByAdjustment(guess, weapon, atmosphere, distance, observed.DropAdjustment().Convert(unit.AngularMil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, bc.Value(), 0.243, 0.0005, "BC by adjustment")
+
+	_, _, err = calc.TrueBallisticCoefficient(guess, weapon, atmosphere, distance, unit.MustCreateDistance(1, unit.DistanceInch))
+	if err == nil {
+		t.Error("Drop above the line of sight must be unreachable")
+	}
+}
+
+func TestTrueFormFactor(t *testing.T) {
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	distance := unit.MustCreateDistance(800, unit.DistanceYard)
+	var ammunition = func(formFactor float64) externalballistics.Ammunition {
+		bc, _ := externalballistics.CreateBallisticCoefficientForCustomDragTable(formFactor, externalballistics.FF, customTable)
+		projectile := externalballistics.CreateProjectileWithDimensions(bc, unit.MustCreateDistance(0.308, unit.DistanceInch),
+			unit.MustCreateDistance(1.2, unit.DistanceInch), unit.MustCreateWeight(168, unit.WeightGrain))
+		return externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	}
+
+	actual := ammunition(1)
+	shotInfo := externalballistics.CreateShotParameters(calc.SightAngle(actual, weapon, atmosphere), distance, distance)
+	observed := calc.Trajectory(actual, weapon, atmosphere, shotInfo, nil)[1]
+
+	for _, guess := range []float64{1.1, 0.9} {
+		ff, result, err := calc.TrueBallisticCoefficient(ammunition(guess), weapon, atmosphere, distance, observed.Drop())
+		if err != nil {
+			t.Fatalf("Truing from FF %g: %v", guess, err)
+		}
+		if !result.Converged() {
+			t.Errorf("Truing from FF %g didn't converge: %d iterations, residual %s", guess, result.Iterations(), result.Residual())
+		}
+		if ff.ValueType() != externalballistics.FF {
+			t.Error("Value type must not be changed")
+		}
+		assertEqual(t, ff.Value(), 1, 0.002, "FF")
+	}
+}
+
+func TestTrueMuzzleVelocity(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	distance := unit.MustCreateDistance(700, unit.DistanceYard)
+
+	actual := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2690, unit.VelocityFPS))
+	shotInfo := externalballistics.CreateShotParameters(calc.SightAngle(actual, weapon, atmosphere), distance, distance)
+	observed := calc.Trajectory(actual, weapon, atmosphere, shotInfo, nil)[1]
+
+	guess := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(838, unit.VelocityMPS))
+	velocity, result, err := calc.TrueMuzzleVelocity(guess, weapon, atmosphere, distance, observed.Drop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Converged() {
+		t.Errorf("Truing didn't converge: %d iterations, residual %s", result.Iterations(), result.Residual())
+	}
+	assertEqual(t, velocity.In(unit.VelocityFPS), 2690, 1, "Velocity")
+	if velocity.Units() != unit.VelocityMPS {
+		t.Error("Velocity must be in the units of the ammunition")
+	}
+
+	velocity, _, err = calc.TrueMuzzleVelocityByAdjustment(guess, weapon, atmosphere, distance, observed.DropAdjustment())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, velocity.In(unit.VelocityFPS), 2690, 1, "Velocity by adjustment")
+
+	_, _, err = calc.TrueMuzzleVelocity(guess, weapon, atmosphere, unit.MustCreateDistance(1500, unit.DistanceYard), unit.MustCreateDistance(-1900, unit.DistanceInch))
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) || errors.Is(err, externalballistics.ErrUnreachableTarget) {
+		t.Errorf("Subsonic distance must be rejected as invalid parameter, got %v", err)
+	}
+}
+
+func TestTrajectoryTermination(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+
+	shotInfo := externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard))
+	result := calc.CalculateTrajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	if !result.IsComplete() || result.Termination() != externalballistics.TerminationMaximumRange {
+		t.Errorf("Termination: expected complete trajectory, got %d", result.Termination())
+	}
+	assertEqual(t, float64(len(result.Data())), 11, 0.1, "Length")
+
+	//the projectile falls below the maximum drop or slows down before it reaches 50 miles
+	shotInfo = externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(50, unit.DistanceMile), unit.MustCreateDistance(100, unit.DistanceYard))
+	result = calc.CalculateTrajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	if result.IsComplete() {
+		t.Errorf("Termination: expected incomplete trajectory")
+	}
+	data := result.Data()
+	if len(data) < 2 || len(data) >= 880 {
+		t.Fatalf("Length: unexpected length %d", len(data))
+	}
+	for i := 1; i < len(data); i++ {
+		if data[i].TravelledDistance().In(unit.DistanceYard) <= data[i-1].TravelledDistance().In(unit.DistanceYard) {
+			t.Errorf("Distance: row %d is not calculated", i)
+		}
+	}
+	if len(calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)) != len(data) {
+		t.Errorf("Length: Trajectory must return computed rows only")
+	}
+
+	calc.SetGroundLevel(unit.MustCreateDistance(-10, unit.DistanceFoot))
+	shotInfo = externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(2000, unit.DistanceYard), unit.MustCreateDistance(10, unit.DistanceYard))
+	result = calc.CalculateTrajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	if result.Termination() != externalballistics.TerminationGroundHit {
+		t.Errorf("Termination: expected ground hit, got %d", result.Termination())
+	}
+	data = result.Data()
+	var last = data[len(data)-1]
+	assertEqual(t, last.Drop().In(unit.DistanceFoot), -10, 1.5, "Drop at ground")
+}
+
+func TestCalculatorThresholds(t *testing.T) {
+	calc := externalballistics.CreateTrajectoryCalculator()
+	assertEqual(t, calc.MinimumVelocity().In(unit.VelocityFPS), 50, 1e-7, "Default minimum velocity")
+	assertEqual(t, calc.MaximumDrop().In(unit.DistanceFoot), -15000, 1e-7, "Default maximum drop")
+	assertEqual(t, float64(calc.MaxIterations()), 10, 0.1, "Default max iterations")
+	assertEqual(t, calc.ZeroFindingAccuracy().In(unit.DistanceFoot), 0.000005, 1e-12, "Default zero finding accuracy")
+
+	if calc.SetMinimumVelocity(unit.MustCreateVelocity(-1, unit.VelocityFPS)) == nil {
+		t.Errorf("SetMinimumVelocity: negative velocity must be rejected")
+	}
+	if calc.SetMaximumDrop(unit.MustCreateDistance(10, unit.DistanceFoot)) == nil {
+		t.Errorf("SetMaximumDrop: positive drop must be rejected")
+	}
+	if calc.SetMaxIterations(0) == nil {
+		t.Errorf("SetMaxIterations: zero iterations must be rejected")
+	}
+	if calc.SetZeroFindingAccuracy(unit.MustCreateDistance(0, unit.DistanceFoot)) == nil {
+		t.Errorf("SetZeroFindingAccuracy: zero accuracy must be rejected")
+	}
+	assertEqual(t, calc.MinimumVelocity().In(unit.VelocityFPS), 50, 1e-7, "Minimum velocity after rejected change")
+
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+	shotInfo := externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(3000, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard))
+
+	if err := calc.SetMinimumVelocity(unit.MustCreateVelocity(1000, unit.VelocityFPS)); err != nil {
+		t.Fatalf("SetMinimumVelocity: %v", err)
+	}
+	result := calc.CalculateTrajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	if result.Termination() != externalballistics.TerminationMinimumVelocity {
+		t.Errorf("Termination: expected minimum velocity, got %d", result.Termination())
+	}
+	data := result.Data()
+	if data[len(data)-1].Velocity().In(unit.VelocityFPS) < 1000 {
+		t.Errorf("Velocity: row below the minimum velocity is returned")
+	}
+
+	if err := calc.SetMinimumVelocity(unit.MustCreateVelocity(10, unit.VelocityFPS)); err != nil {
+		t.Fatalf("SetMinimumVelocity: %v", err)
+	}
+	if err := calc.SetMaximumDrop(unit.MustCreateDistance(-100, unit.DistanceFoot)); err != nil {
+		t.Fatalf("SetMaximumDrop: %v", err)
+	}
+	result = calc.CalculateTrajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	if result.Termination() != externalballistics.TerminationMaximumDrop {
+		t.Errorf("Termination: expected maximum drop, got %d", result.Termination())
+	}
+
+	if err := calc.SetZeroFindingAccuracy(unit.MustCreateDistance(1, unit.DistanceInch)); err != nil {
+		t.Fatalf("SetZeroFindingAccuracy: %v", err)
+	}
+	if err := calc.SetMaxIterations(1); err != nil {
+		t.Fatalf("SetMaxIterations: %v", err)
+	}
+	coarse := calc.SightAngle(ammo, weapon, atmosphere)
+	if math.Abs(coarse.In(unit.AngularMOA)-sightAngle.In(unit.AngularMOA)) < 1e-6 {
+		t.Errorf("SightAngle: a single iteration must not reach the default accuracy")
+	}
+}
+
+func TestSightAngleChecked(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+
+	sightAngle, err := calc.SightAngleChecked(ammo, weapon, atmosphere)
+	if err != nil {
+		t.Fatalf("SightAngleChecked: unexpected error %v", err)
+	}
+	assertEqual(t, sightAngle.In(unit.AngularRadian), calc.SightAngle(ammo, weapon, atmosphere).In(unit.AngularRadian), 1e-12, "Sight angle")
+
+	_ = calc.SetMaxIterations(1)
+	_, err = calc.SightAngleChecked(ammo, weapon, atmosphere)
+	zeroError, ok := err.(*externalballistics.ZeroFindingError)
+	if !ok {
+		t.Fatalf("SightAngleChecked: expected ZeroFindingError, got %v", err)
+	}
+	if zeroError.Reason() != externalballistics.ZeroFindingNonConvergence || zeroError.Iterations() != 1 {
+		t.Errorf("SightAngleChecked: expected non-convergence after 1 iteration, got %d after %d", zeroError.Reason(), zeroError.Iterations())
+	}
+	if zeroError.Residual().In(unit.DistanceFoot) <= calc.ZeroFindingAccuracy().In(unit.DistanceFoot) {
+		t.Errorf("SightAngleChecked: the residual %s must exceed the accuracy", zeroError.Residual())
+	}
+
+	calc = externalballistics.CreateTrajectoryCalculator()
+	_ = calc.SetMinimumVelocity(unit.MustCreateVelocity(1500, unit.VelocityFPS))
+	weapon = externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch),
+		externalballistics.CreateZeroInfo(unit.MustCreateDistance(1000, unit.DistanceYard)))
+	_, err = calc.SightAngleChecked(ammo, weapon, atmosphere)
+	zeroError, ok = err.(*externalballistics.ZeroFindingError)
+	if !ok {
+		t.Fatalf("SightAngleChecked: expected ZeroFindingError, got %v", err)
+	}
+	if zeroError.Reason() != externalballistics.ZeroFindingUnreachable {
+		t.Errorf("SightAngleChecked: expected unreachable zero, got %d", zeroError.Reason())
+	}
+	if zeroError.Residual().In(unit.DistanceYard) <= 0 || zeroError.Residual().In(unit.DistanceYard) >= 1000 {
+		t.Errorf("SightAngleChecked: unexpected residual %s", zeroError.Residual())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, err := externalballistics.CreateBallisticCoefficient(0.3, 100)
+	if !errors.Is(err, externalballistics.ErrInvalidDragTable) {
+		t.Errorf("CreateBallisticCoefficient: expected invalid drag table, got %v", err)
+	}
+	_, err = externalballistics.CreateBallisticCoefficient(0.3, externalballistics.DragTableGC)
+	if !errors.Is(err, externalballistics.ErrInvalidDragTable) {
+		t.Errorf("CreateBallisticCoefficient: expected invalid drag table for custom table id, got %v", err)
+	}
+	_, err = externalballistics.CreateBallisticCoefficientForCustomDragTable(0.3, externalballistics.BC, []externalballistics.DataPoint{{A: 1, B: 0.3}})
+	if !errors.Is(err, externalballistics.ErrInvalidDragTable) {
+		t.Errorf("CreateBallisticCoefficientForCustomDragTable: expected invalid drag table, got %v", err)
+	}
+	_, err = externalballistics.CreateBallisticCoefficient(-0.3, externalballistics.DragTableG1)
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CreateBallisticCoefficient: expected invalid parameter for negative value, got %v", err)
+	}
+	_, err = externalballistics.CreateBallisticCoefficientForCustomDragFunction(0.3, 3, customDragFunction)
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CreateBallisticCoefficientForCustomDragFunction: expected invalid parameter for value type, got %v", err)
+	}
+	_, err = externalballistics.CreateMultipleBallisticCoefficient(externalballistics.DragTableG1)
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CreateMultipleBallisticCoefficient: expected invalid parameter for empty values, got %v", err)
+	}
+	_, err = externalballistics.CreateAdaptiveIntegrator(unit.MustCreateDistance(0, unit.DistanceInch))
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CreateAdaptiveIntegrator: expected invalid parameter for zero tolerance, got %v", err)
+	}
+	_, err = externalballistics.LoadDragTableCSV(strings.NewReader("mach,cd\n0.5,x\n"))
+	if !errors.Is(err, externalballistics.ErrInvalidDragTable) {
+		t.Errorf("LoadDragTableCSV: expected invalid drag table, got %v", err)
+	}
+	_, err = externalballistics.LoadDragTableFile(filepath.Join(os.TempDir(), "no-such-drag-table.csv"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadDragTableFile: expected missing file, got %v", err)
+	}
+
+	_, err = externalballistics.CreateAtmosphere(unit.MustCreateDistance(0, unit.DistanceFoot),
+		unit.MustCreatePressure(29.92, unit.PressureInHg), unit.MustCreateTemperature(59, unit.TemperatureFahrenheit), 120)
+	if !errors.Is(err, externalballistics.ErrInvalidHumidity) {
+		t.Errorf("CreateAtmosphere: expected invalid humidity, got %v", err)
+	}
+
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch),
+		externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard)))
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+
+	if err = calc.SetMaxIterations(0); !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("SetMaxIterations: expected invalid parameter, got %v", err)
+	}
+	_ = calc.SetMaxIterations(1)
+	_, err = calc.SightAngleChecked(ammo, weapon, atmosphere)
+	if !errors.Is(err, externalballistics.ErrNonConvergence) {
+		t.Errorf("SightAngleChecked: expected non-convergence, got %v", err)
+	}
+	var zeroError *externalballistics.ZeroFindingError
+	if !errors.As(err, &zeroError) || zeroError.Iterations() != 1 {
+		t.Errorf("SightAngleChecked: expected ZeroFindingError, got %v", err)
+	}
+
+	//the drop can't be calculated when the weapon can't be zeroed
+	_, _, err = calc.TrueBallisticCoefficient(ammo, weapon, atmosphere,
+		unit.MustCreateDistance(500, unit.DistanceYard), unit.MustCreateDistance(-50, unit.DistanceInch))
+	if !errors.Is(err, externalballistics.ErrNonConvergence) {
+		t.Errorf("TrueBallisticCoefficient: expected non-convergence when the zero isn't found, got %v", err)
+	}
+
+	calc = externalballistics.CreateTrajectoryCalculator()
+	_, _, err = calc.TrueBallisticCoefficient(ammo, weapon, atmosphere,
+		unit.MustCreateDistance(500, unit.DistanceYard), unit.MustCreateDistance(10, unit.DistanceFoot))
+	if !errors.Is(err, externalballistics.ErrUnreachableTarget) {
+		t.Errorf("TrueBallisticCoefficient: expected unreachable target, got %v", err)
+	}
+}
+
+func TestCheckedConstructors(t *testing.T) {
+	var expectInvalid = func(err error, name string) {
+		if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+			t.Errorf("%s: expected invalid parameter, got %v", name, err)
+		}
+	}
+	var expectValid = func(err error, name string) {
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+	}
+
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	sightHeight := unit.MustCreateDistance(2, unit.DistanceInch)
+	_, err := externalballistics.CreateWeaponChecked(sightHeight, zero)
+	expectValid(err, "CreateWeaponChecked")
+	_, err = externalballistics.CreateWeaponChecked(sightHeight, externalballistics.CreateZeroInfo(unit.MustCreateDistance(0, unit.DistanceYard)))
+	expectInvalid(err, "CreateWeaponChecked zero distance")
+	_, err = externalballistics.CreateWeaponWithTwistChecked(sightHeight, zero, externalballistics.CreateTwist(externalballistics.TwistRight, unit.MustCreateDistance(10, unit.DistanceInch)))
+	expectValid(err, "CreateWeaponWithTwistChecked")
+	_, err = externalballistics.CreateWeaponWithTwistChecked(sightHeight, zero, externalballistics.CreateTwist(3, unit.MustCreateDistance(10, unit.DistanceInch)))
+	expectInvalid(err, "CreateWeaponWithTwistChecked direction")
+	_, err = externalballistics.CreateWeaponWithTwistChecked(sightHeight, zero, externalballistics.CreateTwist(externalballistics.TwistLeft, unit.MustCreateDistance(0, unit.DistanceInch)))
+	expectInvalid(err, "CreateWeaponWithTwistChecked twist")
+
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	ff, _ := externalballistics.CreateBallisticCoefficientForCustomDragFunction(1.1, externalballistics.FF, func(mach float64) float64 { return 0.3 })
+	weight := unit.MustCreateWeight(168, unit.WeightGrain)
+	diameter := unit.MustCreateDistance(0.308, unit.DistanceInch)
+	length := unit.MustCreateDistance(1.2, unit.DistanceInch)
+
+	projectile, err := externalballistics.CreateProjectileChecked(bc, weight)
+	expectValid(err, "CreateProjectileChecked")
+	_, err = externalballistics.CreateProjectileChecked(externalballistics.BallisticCoefficient{}, weight)
+	expectInvalid(err, "CreateProjectileChecked empty BC")
+	_, err = externalballistics.CreateProjectileChecked(bc, unit.MustCreateWeight(0, unit.WeightGrain))
+	expectInvalid(err, "CreateProjectileChecked weight")
+	_, err = externalballistics.CreateProjectileChecked(ff, weight)
+	expectInvalid(err, "CreateProjectileChecked form factor")
+	_, err = externalballistics.CreateProjectileWithDimensionsChecked(ff, diameter, length, weight)
+	expectValid(err, "CreateProjectileWithDimensionsChecked")
+	_, err = externalballistics.CreateProjectileWithDimensionsChecked(ff, unit.MustCreateDistance(0, unit.DistanceInch), length, weight)
+	expectInvalid(err, "CreateProjectileWithDimensionsChecked diameter")
+	_, err = externalballistics.CreateProjectileWithDimensionsChecked(bc, diameter, unit.MustCreateDistance(-1, unit.DistanceInch), weight)
+	expectInvalid(err, "CreateProjectileWithDimensionsChecked length")
+
+	_, err = externalballistics.CreateAmmunitionChecked(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	expectValid(err, "CreateAmmunitionChecked")
+	_, err = externalballistics.CreateAmmunitionChecked(projectile, unit.MustCreateVelocity(0, unit.VelocityFPS))
+	expectInvalid(err, "CreateAmmunitionChecked velocity")
+
+	sightAngle := unit.MustCreateAngular(0.1, unit.AngularDegree)
+	maxDistance := unit.MustCreateDistance(1000, unit.DistanceYard)
+	step := unit.MustCreateDistance(100, unit.DistanceYard)
+	_, err = externalballistics.CreateShotParametersChecked(sightAngle, maxDistance, step)
+	expectValid(err, "CreateShotParametersChecked")
+	_, err = externalballistics.CreateShotParametersChecked(sightAngle, maxDistance, unit.MustCreateDistance(0, unit.DistanceYard))
+	expectInvalid(err, "CreateShotParametersChecked zero step")
+	_, err = externalballistics.CreateShotParametersChecked(sightAngle, step, maxDistance)
+	expectInvalid(err, "CreateShotParametersChecked step greater than distance")
+	_, err = externalballistics.CreateShotParameterUnlevelChecked(sightAngle, maxDistance, step, unit.MustCreateAngular(30, unit.AngularDegree), unit.MustCreateAngular(0, unit.AngularDegree))
+	expectValid(err, "CreateShotParameterUnlevelChecked")
+	_, err = externalballistics.CreateShotParameterUnlevelChecked(sightAngle, maxDistance, step, unit.MustCreateAngular(90, unit.AngularDegree), unit.MustCreateAngular(0, unit.AngularDegree))
+	expectInvalid(err, "CreateShotParameterUnlevelChecked shot angle")
+	_, err = externalballistics.CreateShotParametersByTimeChecked(sightAngle, time.Second, 100*time.Millisecond)
+	expectValid(err, "CreateShotParametersByTimeChecked")
+	_, err = externalballistics.CreateShotParametersByTimeChecked(sightAngle, time.Second, 0)
+	expectInvalid(err, "CreateShotParametersByTimeChecked zero step")
+	_, err = externalballistics.CreateShotParameterUnlevelByTimeChecked(sightAngle, time.Second, 2*time.Second, unit.MustCreateAngular(0, unit.AngularDegree), unit.MustCreateAngular(0, unit.AngularDegree))
+	expectInvalid(err, "CreateShotParameterUnlevelByTimeChecked step greater than time")
+}
+
+func TestTrajectoryInterpolation(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+
+	shotInfo := externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard))
+	result := calc.CalculateTrajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	for i, point := range result.Data() {
+		assertEqual(t, point.TravelledDistance().In(unit.DistanceYard), float64(i)*100, 1e-9, "Distance")
+	}
+
+	reference := calc.Trajectory(ammo, weapon, atmosphere,
+		externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(1, unit.DistanceYard)),
+		nil)
+
+	//both trajectories are integrated with the same calculation step, so the interpolated points must match
+	for _, yards := range []float64{0, 1, 99, 437, 500, 999, 1000} {
+		point, err := result.AtDistance(unit.MustCreateDistance(yards, unit.DistanceYard))
+		if err != nil {
+			t.Errorf("AtDistance: unexpected error %v at %g yd", err, yards)
+			continue
+		}
+		var other = reference[int(yards)]
+		assertEqual(t, point.TravelledDistance().In(unit.DistanceYard), yards, 1e-9, "Distance")
+		assertEqual(t, point.Time().TotalSeconds(), other.Time().TotalSeconds(), 1e-9, "Time")
+		assertEqual(t, point.Drop().In(unit.DistanceInch), other.Drop().In(unit.DistanceInch), 1e-6, "Drop")
+		assertEqual(t, point.Velocity().In(unit.VelocityFPS), other.Velocity().In(unit.VelocityFPS), 1e-6, "Velocity")
+		assertEqual(t, point.DropAdjustment().In(unit.AngularMOA), other.DropAdjustment().In(unit.AngularMOA), 1e-6, "Drop adjustment")
+	}
+
+	between, _ := result.AtDistance(unit.MustCreateDistance(437.5, unit.DistanceYard))
+	if between.Drop().In(unit.DistanceInch) >= reference[437].Drop().In(unit.DistanceInch) ||
+		between.Drop().In(unit.DistanceInch) <= reference[438].Drop().In(unit.DistanceInch) {
+		t.Errorf("AtDistance: the drop %s at 437.5 yd is not between the drops at 437 and 438 yd", between.Drop())
+	}
+
+	exact, _ := result.AtDistance(unit.MustCreateDistance(500, unit.DistanceYard))
+	assertEqual(t, exact.Drop().In(unit.DistanceInch), result.Data()[5].Drop().In(unit.DistanceInch), 1e-9, "Drop at row")
+	assertEqual(t, exact.Time().TotalSeconds(), result.Data()[5].Time().TotalSeconds(), 1e-9, "Time at row")
+
+	_, err := result.AtDistance(unit.MustCreateDistance(1100, unit.DistanceYard))
+	if !errors.Is(err, externalballistics.ErrUnreachableTarget) {
+		t.Errorf("AtDistance: expected unreachable target beyond the trajectory, got %v", err)
+	}
+	_, err = result.AtDistance(unit.MustCreateDistance(-1, unit.DistanceYard))
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("AtDistance: expected invalid parameter for negative distance, got %v", err)
+	}
+}
+
+func TestTrajectoryForDistances(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+
+	var yards = []float64{1000, 25, 50, 100, 150, 200, 300, 400, 500, 600, 800}
+	var distances []unit.Distance
+	for _, y := range yards {
+		distances = append(distances, unit.MustCreateDistance(y, unit.DistanceYard))
+	}
+	shotInfo := externalballistics.CreateShotParametersForDistances(sightAngle, distances)
+	if !shotInfo.HasDistances() || len(shotInfo.Distances()) != len(yards) {
+		t.Fatalf("ShotParameters: distances are not set")
+	}
+	assertEqual(t, shotInfo.MaximumDistance().In(unit.DistanceYard), 1000, 1e-9, "Maximum distance")
+
+	result := calc.CalculateTrajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	if !result.IsComplete() {
+		t.Errorf("Termination: expected complete trajectory, got %d", result.Termination())
+	}
+	data := result.Data()
+	if len(data) != len(yards) {
+		t.Fatalf("Length: expected %d rows, got %d", len(yards), len(data))
+	}
+
+	var expected = []float64{25, 50, 100, 150, 200, 300, 400, 500, 600, 800, 1000}
+	for i, point := range data {
+		assertEqual(t, point.TravelledDistance().In(unit.DistanceYard), expected[i], 1e-9, "Distance")
+		other, err := result.AtDistance(point.TravelledDistance())
+		if err != nil {
+			t.Fatalf("AtDistance: %v", err)
+		}
+		assertEqual(t, point.Drop().In(unit.DistanceInch), other.Drop().In(unit.DistanceInch), 1e-9, "Drop")
+	}
+
+	stepped := calc.Trajectory(ammo, weapon, atmosphere,
+		externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(25, unit.DistanceYard)),
+		nil)
+	assertEqual(t, data[10].Drop().In(unit.DistanceInch), stepped[40].Drop().In(unit.DistanceInch), 0.01, "Drop at 1000 yd")
+
+	_, err := externalballistics.CreateShotParametersForDistancesChecked(sightAngle, nil)
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CreateShotParametersForDistancesChecked: expected invalid parameter for empty list, got %v", err)
+	}
+	_, err = externalballistics.CreateShotParametersForDistancesChecked(sightAngle, []unit.Distance{unit.MustCreateDistance(-5, unit.DistanceYard)})
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CreateShotParametersForDistancesChecked: expected invalid parameter for negative distance, got %v", err)
+	}
+}
+
+func TestTrajectoryEvents(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(200, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	if err := calc.SetEnergyThreshold(unit.MustCreateEnergy(1000, unit.EnergyFootPound)); err != nil {
+		t.Fatalf("SetEnergyThreshold: %v", err)
+	}
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+
+	shotInfo := externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(1200, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard))
+	result := calc.CalculateTrajectory(ammo, weapon, atmosphere, shotInfo, nil)
+
+	var found = make(map[byte]externalballistics.TrajectoryData)
+	var previous float64
+	for _, event := range result.Events() {
+		if _, ok := found[event.Kind()]; ok {
+			t.Errorf("Events: event %s is reported twice", event.Name())
+		}
+		found[event.Kind()] = event.Data()
+		var distance = event.Data().TravelledDistance().In(unit.DistanceYard)
+		if distance < previous {
+			t.Errorf("Events: event %s is out of order", event.Name())
+		}
+		previous = distance
+	}
+	for _, kind := range []byte{externalballistics.EventNearZero, externalballistics.EventFarZero, externalballistics.EventApex,
+		externalballistics.EventTransonic, externalballistics.EventSubsonic, externalballistics.EventEnergyThreshold} {
+		if _, ok := found[kind]; !ok {
+			t.Errorf("Events: event %d is not found", kind)
+		}
+	}
+
+	var near = found[externalballistics.EventNearZero]
+	var far = found[externalballistics.EventFarZero]
+	var apex = found[externalballistics.EventApex]
+	assertEqual(t, far.TravelledDistance().In(unit.DistanceYard), 200, 0.1, "Far zero")
+	assertEqual(t, far.Drop().In(unit.DistanceInch), 0, 1e-3, "Drop at far zero")
+	assertEqual(t, near.Drop().In(unit.DistanceInch), 0, 1e-3, "Drop at near zero")
+	if near.TravelledDistance().In(unit.DistanceYard) <= 0 || near.TravelledDistance().In(unit.DistanceYard) >= apex.TravelledDistance().In(unit.DistanceYard) {
+		t.Errorf("Events: near zero %s must be before apex %s", near.TravelledDistance(), apex.TravelledDistance())
+	}
+	if apex.TravelledDistance().In(unit.DistanceYard) >= 200 {
+		t.Errorf("Events: apex %s must be before far zero", apex.TravelledDistance())
+	}
+	for _, yards := range []float64{50, 100, 150} {
+		point, _ := result.AtDistance(unit.MustCreateDistance(yards, unit.DistanceYard))
+		if point.Drop().In(unit.DistanceInch) > apex.Drop().In(unit.DistanceInch) {
+			t.Errorf("Events: the projectile at %g yd is higher than at apex", yards)
+		}
+	}
+
+	assertEqual(t, found[externalballistics.EventTransonic].MachVelocity(), 1.2, 1e-6, "Mach at transonic")
+	assertEqual(t, found[externalballistics.EventSubsonic].MachVelocity(), 1, 1e-6, "Mach at subsonic")
+	assertEqual(t, found[externalballistics.EventEnergyThreshold].Energy().In(unit.EnergyFootPound), 1000, 0.5, "Energy at threshold")
+}
+
+func TestPointBlankRange(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+
+	pbr, err := calc.CalculatePointBlankRange(ammo, weapon, atmosphere, unit.MustCreateDistance(6, unit.DistanceInch))
+	if err != nil {
+		t.Fatalf("CalculatePointBlankRange: %v", err)
+	}
+	assertEqual(t, pbr.MaximumOrdinate().In(unit.DistanceInch), 3, 1e-9, "Maximum ordinate")
+	if !(pbr.NearZero().In(unit.DistanceYard) < pbr.MaximumOrdinateDistance().In(unit.DistanceYard) &&
+		pbr.MaximumOrdinateDistance().In(unit.DistanceYard) < pbr.FarZero().In(unit.DistanceYard) &&
+		pbr.FarZero().In(unit.DistanceYard) < pbr.Range().In(unit.DistanceYard)) {
+		t.Errorf("PointBlankRange: near zero, apex, far zero and range are out of order")
+	}
+	assertEqual(t, pbr.ZeroDistance().In(unit.DistanceYard), pbr.FarZero().In(unit.DistanceYard), 1e-9, "Zero distance")
+
+	//the distances are in the units of the zero distance, and only the maximum ordinate is in the units of the vital zone
+	metricWeapon := externalballistics.CreateWeapon(weapon.SightHeight(), externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceMeter)))
+	metric, _ := calc.CalculatePointBlankRange(ammo, metricWeapon, atmosphere, unit.MustCreateDistance(15.24, unit.DistanceCentimeter))
+	for _, distance := range []unit.Distance{metric.Range(), metric.NearZero(), metric.FarZero(), metric.ZeroDistance(), metric.MaximumOrdinateDistance()} {
+		if distance.Units() != unit.DistanceMeter {
+			t.Errorf("PointBlankRange: the distance %s must be in meters", distance)
+		}
+	}
+	if metric.MaximumOrdinate().Units() != unit.DistanceCentimeter {
+		t.Errorf("PointBlankRange: the maximum ordinate %s must be in centimeters", metric.MaximumOrdinate())
+	}
+	if pbr.Range().Units() != unit.DistanceYard || pbr.MaximumOrdinate().Units() != unit.DistanceInch {
+		t.Errorf("PointBlankRange: the range %s must be in yards and the maximum ordinate %s in inches", pbr.Range(), pbr.MaximumOrdinate())
+	}
+	assertEqual(t, metric.Range().In(unit.DistanceYard), pbr.Range().In(unit.DistanceYard), 0.01, "Range in meters")
+
+	//the weapon zeroed at the optimal distance must have the same sight angle and trajectory
+	zeroed := externalballistics.CreateWeapon(weapon.SightHeight(), externalballistics.CreateZeroInfo(pbr.ZeroDistance()))
+	sightAngle := calc.SightAngle(ammo, zeroed, atmosphere)
+	assertEqual(t, sightAngle.In(unit.AngularMOA), pbr.SightAngle().In(unit.AngularMOA), 0.01, "Sight angle")
+
+	result := calc.CalculateTrajectory(ammo, zeroed, atmosphere,
+		externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(500, unit.DistanceYard), unit.MustCreateDistance(1, unit.DistanceYard)),
+		nil)
+	apex, _ := result.AtDistance(pbr.MaximumOrdinateDistance())
+	assertEqual(t, apex.Drop().In(unit.DistanceInch), 3, 0.01, "Drop at apex")
+	limit, _ := result.AtDistance(pbr.Range())
+	assertEqual(t, limit.Drop().In(unit.DistanceInch), -3, 0.02, "Drop at point blank range")
+
+	_, err = calc.CalculatePointBlankRange(ammo, weapon, atmosphere, unit.MustCreateDistance(0, unit.DistanceInch))
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CalculatePointBlankRange: expected invalid parameter, got %v", err)
+	}
+}
+
+func TestFiringSolution(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	zero := externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), zero)
+	weapon.SetClickValue(unit.MustCreateAngular(0.25, unit.AngularMOA))
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	wind := externalballistics.CreateOnlyWindInfo(unit.MustCreateVelocity(10, unit.VelocityMPH), unit.MustCreateAngular(-90, unit.AngularDegree))
+	calc := externalballistics.CreateTrajectoryCalculator()
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+
+	solution, err := calc.CalculateFiringSolution(ammo, weapon, atmosphere, wind,
+		unit.MustCreateDistance(500, unit.DistanceYard), unit.MustCreateAngular(0, unit.AngularDegree), unit.AngularMil)
+	if err != nil {
+		t.Fatalf("CalculateFiringSolution: %v", err)
+	}
+
+	data := calc.Trajectory(ammo, weapon, atmosphere,
+		externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(500, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard)),
+		wind)
+	impact := data[5]
+	if solution.Elevation().Units() != unit.AngularMil || solution.Windage().Units() != unit.AngularMil {
+		t.Errorf("CalculateFiringSolution: the adjustments must be in the units requested")
+	}
+	assertEqual(t, solution.Elevation().In(unit.AngularMOA), -impact.DropAdjustment().In(unit.AngularMOA), 0.01, "Elevation")
+	assertEqual(t, solution.Windage().In(unit.AngularMOA), -impact.WindageAdjustment().In(unit.AngularMOA), 0.01, "Windage")
+	if solution.Elevation().In(unit.AngularMOA) <= 0 {
+		t.Errorf("CalculateFiringSolution: the sight must be adjusted up")
+	}
+	assertEqual(t, solution.ElevationClicks(), solution.Elevation().In(unit.AngularMOA)*4, 1e-6, "Elevation clicks")
+	assertEqual(t, solution.WindageClicks(), solution.Windage().In(unit.AngularMOA)*4, 1e-6, "Windage clicks")
+	assertEqual(t, solution.TimeOfFlight().TotalSeconds(), impact.Time().TotalSeconds(), 1e-3, "Time of flight")
+	assertEqual(t, solution.Velocity().In(unit.VelocityFPS), impact.Velocity().In(unit.VelocityFPS), 0.1, "Velocity")
+	assertEqual(t, solution.Energy().In(unit.EnergyFootPound), impact.Energy().In(unit.EnergyFootPound), 0.2, "Energy")
+
+	//shooting uphill or downhill requires less elevation than the level shot at the same distance
+	for _, degrees := range []float64{30, -30} {
+		sloped, err := calc.CalculateFiringSolution(ammo, weapon, atmosphere, nil,
+			unit.MustCreateDistance(500, unit.DistanceYard), unit.MustCreateAngular(degrees, unit.AngularDegree), unit.AngularMOA)
+		if err != nil {
+			t.Fatalf("CalculateFiringSolution: %v", err)
+		}
+		if sloped.Elevation().In(unit.AngularMOA) >= solution.Elevation().In(unit.AngularMOA) {
+			t.Errorf("CalculateFiringSolution: the elevation %s at %g degrees must be less than %s", sloped.Elevation(), degrees, solution.Elevation())
+		}
+	}
+
+	_, err = calc.CalculateFiringSolution(ammo, weapon, atmosphere, nil, unit.MustCreateDistance(0, unit.DistanceYard), unit.MustCreateAngular(0, unit.AngularDegree), unit.AngularMOA)
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CalculateFiringSolution: expected invalid parameter, got %v", err)
+	}
+	_, err = calc.CalculateFiringSolution(ammo, weapon, atmosphere, nil, unit.MustCreateDistance(500, unit.DistanceYard), unit.MustCreateAngular(0, unit.AngularDegree), 255)
+	if !errors.Is(err, unit.ErrUnsupportedUnit) {
+		t.Errorf("CalculateFiringSolution: expected unsupported unit, got %v", err)
+	}
+}
+
+func TestICAOAtmosphereLayers(t *testing.T) {
+	//geopotential altitude (m), temperature (K) and pressure (Pa) of the ICAO standard atmosphere
+	var reference = [][]float64{
+		{0, 288.15, 101325},
+		{5000, 255.65, 54019.9},
+		{11000, 216.65, 22632.1},
+		{15000, 216.65, 12044.6},
+		{20000, 216.65, 5474.89},
+		{32000, 228.65, 868.019},
+		{47000, 270.65, 110.906},
+		{51000, 270.65, 66.9389},
+		{71000, 214.65, 3.95642},
+		{79006, 198.639, 1.0524},
+	}
+	const earthRadius = 6356766.0
+	for _, row := range reference {
+		var geometric = earthRadius * row[0] / (earthRadius - row[0])
+		var atmosphere = externalballistics.CreateICAOAtmosphere(unit.MustCreateDistance(geometric, unit.DistanceMeter))
+		assertEqual(t, atmosphere.Temperature().In(unit.TemperatureKelvin), row[1], 0.01, "Temperature")
+		//the standard pressure is 29.92 inHg
+		var ratio = atmosphere.Pressure().In(unit.PressureInHg) / 29.92
+		assertEqual(t, ratio/(row[2]/101325), 1, 0.0005, "Pressure")
+	}
+}
+
+func TestTrajectoryInTropopause(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch),
+		externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard)))
+	atmosphere := externalballistics.CreateICAOAtmosphere(unit.MustCreateDistance(12000, unit.DistanceMeter))
+	calc := externalballistics.CreateTrajectoryCalculator()
+	sightAngle := calc.SightAngle(ammo, weapon, atmosphere)
+
+	//the temperature and so the speed of sound don't change within the tropopause
+	data := calc.Trajectory(ammo, weapon, atmosphere,
+		externalballistics.CreateShotParameters(sightAngle, unit.MustCreateDistance(3000, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard)),
+		nil)
+	if data[len(data)-1].Drop().In(unit.DistanceFoot) > -100 {
+		t.Fatalf("Drop: the projectile must leave the altitude of the atmosphere")
+	}
+	for _, point := range data {
+		assertEqual(t, point.Velocity().In(unit.VelocityFPS)/point.MachVelocity(), atmosphere.Mach().In(unit.VelocityFPS), 0.01, "Speed of sound")
+	}
+}
+
+func TestColdAtmospherePressure(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch),
+		externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard)))
+	calc := externalballistics.CreateTrajectoryCalculator()
+	shot := externalballistics.CreateShotParameters(unit.MustCreateAngular(0, unit.AngularMOA),
+		unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(1000, unit.DistanceYard))
+
+	velocityAt := func(atmosphere externalballistics.Atmosphere) float64 {
+		data := calc.Trajectory(ammo, weapon, atmosphere, shot, nil)
+		return data[len(data)-1].Velocity().In(unit.VelocityFPS)
+	}
+
+	//below 0°F the thinner air must still slow the projectile down less
+	dense, _ := externalballistics.CreateAtmosphere(unit.MustCreateDistance(0, unit.DistanceFoot),
+		unit.MustCreatePressure(29.92, unit.PressureInHg), unit.MustCreateTemperature(-10, unit.TemperatureFahrenheit), 0)
+	thin, _ := externalballistics.CreateAtmosphere(unit.MustCreateDistance(0, unit.DistanceFoot),
+		unit.MustCreatePressure(20, unit.PressureInHg), unit.MustCreateTemperature(-10, unit.TemperatureFahrenheit), 0)
+	if velocityAt(thin) <= velocityAt(dense) {
+		t.Errorf("Velocity: the lower pressure must reduce the drag below 0°F")
+	}
+
+	//the ICAO standard atmosphere is much colder than 0°F in the tropopause, but still thinner than at the sea level
+	if velocityAt(externalballistics.CreateICAOAtmosphere(unit.MustCreateDistance(12000, unit.DistanceMeter))) <=
+		velocityAt(externalballistics.CreateICAOAtmosphere(unit.MustCreateDistance(0, unit.DistanceMeter))) {
+		t.Errorf("Velocity: the air in the tropopause must be thinner than at the sea level")
+	}
+}
+
+func TestDensityAltitude(t *testing.T) {
+	icao := externalballistics.CreateICAOAtmosphere(unit.MustCreateDistance(5000, unit.DistanceFoot))
+	assertEqual(t, icao.DensityAltitude().In(unit.DistanceFoot), 5000, 1, "ICAO density altitude")
+
+	hot, _ := externalballistics.CreateAtmosphere(unit.MustCreateDistance(0, unit.DistanceFoot),
+		unit.MustCreatePressure(29.92, unit.PressureInHg), unit.MustCreateTemperature(95, unit.TemperatureFahrenheit), 0)
+	assertEqual(t, hot.DensityAltitude().In(unit.DistanceFoot), 2275, 20, "Hot day density altitude")
+
+	//humid air is lighter than dry air
+	humid, _ := externalballistics.CreateAtmosphere(unit.MustCreateDistance(0, unit.DistanceFoot),
+		unit.MustCreatePressure(29.92, unit.PressureInHg), unit.MustCreateTemperature(95, unit.TemperatureFahrenheit), 80)
+	if humid.DensityAltitude().In(unit.DistanceFoot) <= hot.DensityAltitude().In(unit.DistanceFoot) {
+		t.Errorf("DensityAltitude: humid air must have higher density altitude")
+	}
+
+	for _, degrees := range []float64{90, 40, -10} {
+		a, err := externalballistics.CreateAtmosphereByDensityAltitudeAndTemperature(unit.MustCreateDistance(1000, unit.DistanceFoot),
+			unit.MustCreateDistance(6000, unit.DistanceFoot), unit.MustCreateTemperature(degrees, unit.TemperatureFahrenheit))
+		if err != nil {
+			t.Fatalf("CreateAtmosphereByDensityAltitudeAndTemperature: %v", err)
+		}
+		assertEqual(t, a.DensityAltitude().In(unit.DistanceFoot), 6000, 1, "Density altitude")
+		assertEqual(t, a.Temperature().In(unit.TemperatureFahrenheit), degrees, 1e-9, "Temperature")
+		assertEqual(t, a.Altitude().In(unit.DistanceFoot), 1000, 1e-9, "Altitude")
+	}
+
+	a := externalballistics.CreateAtmosphereByDensityAltitude(unit.MustCreateDistance(2000, unit.DistanceFoot), unit.MustCreateDistance(6000, unit.DistanceFoot))
+	assertEqual(t, a.DensityAltitude().In(unit.DistanceFoot), 6000, 1, "Density altitude")
+	assertEqual(t, a.Temperature().In(unit.TemperatureFahrenheit), 51.87, 0.01, "Standard temperature")
+
+	_, err := externalballistics.CreateAtmosphereByDensityAltitudeAndTemperature(unit.MustCreateDistance(0, unit.DistanceFoot),
+		unit.MustCreateDistance(0, unit.DistanceFoot), unit.MustCreateTemperature(-500, unit.TemperatureFahrenheit))
+	if !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("CreateAtmosphereByDensityAltitudeAndTemperature: expected invalid parameter, got %v", err)
+	}
+}
+
+func TestBarometricPressure(t *testing.T) {
+	altitude := unit.MustCreateDistance(5000, unit.DistanceFoot)
+	station := externalballistics.StationPressureFromBarometric(unit.MustCreatePressure(29.92, unit.PressureInHg), altitude)
+	assertEqual(t, station.In(unit.PressureInHg), 24.896, 0.005, "Station pressure")
+	assertEqual(t, externalballistics.BarometricPressureFromStation(station, altitude).In(unit.PressureInHg), 29.92, 1e-9, "Barometric pressure")
+
+	hpa := externalballistics.StationPressureFromBarometric(unit.MustCreatePressure(1013.25, unit.PressureHP), unit.MustCreateDistance(0, unit.DistanceMeter))
+	if hpa.Units() != unit.PressureHP {
+		t.Errorf("StationPressureFromBarometric: the pressure must be in the units of the argument")
+	}
+	assertEqual(t, hpa.In(unit.PressureHP), 1013.25, 1e-9, "Pressure at the sea level")
+
+	temperature := unit.MustCreateTemperature(50, unit.TemperatureFahrenheit)
+	byBarometric, err := externalballistics.CreateAtmosphereWithBarometricPressure(altitude, unit.MustCreatePressure(30.12, unit.PressureInHg), temperature, 40)
+	if err != nil {
+		t.Fatalf("CreateAtmosphereWithBarometricPressure: %v", err)
+	}
+	byStation, _ := externalballistics.CreateAtmosphere(altitude,
+		externalballistics.StationPressureFromBarometric(unit.MustCreatePressure(30.12, unit.PressureInHg), altitude), temperature, 40)
+	assertEqual(t, byBarometric.Pressure().In(unit.PressureInHg), byStation.Pressure().In(unit.PressureInHg), 1e-9, "Station pressure")
+	assertEqual(t, byBarometric.DensityAltitude().In(unit.DistanceFoot), byStation.DensityAltitude().In(unit.DistanceFoot), 1e-6, "Density altitude")
+	assertEqual(t, byBarometric.BarometricPressure().In(unit.PressureInHg), 30.12, 1e-9, "Barometric pressure")
+
+	icao := externalballistics.CreateICAOAtmosphere(altitude)
+	assertEqual(t, icao.BarometricPressure().In(unit.PressureInHg), 29.92, 1e-9, "ICAO barometric pressure")
+}
+
+func TestHumidityConstructors(t *testing.T) {
+	altitude := unit.MustCreateDistance(0, unit.DistanceMeter)
+	pressure := unit.MustCreatePressure(1013.25, unit.PressureHP)
+	temperature := unit.MustCreateTemperature(20, unit.TemperatureCelsius)
+
+	a, err := externalballistics.CreateAtmosphereWithDewPoint(altitude, pressure, temperature, unit.MustCreateTemperature(10, unit.TemperatureCelsius))
+	if err != nil {
+		t.Fatalf("CreateAtmosphereWithDewPoint: %v", err)
+	}
+	assertEqual(t, a.Humidity(), 0.525, 0.002, "Humidity from dew point")
+	assertEqual(t, a.DewPoint().In(unit.TemperatureCelsius), 10, 1e-6, "Dew point")
+	if a.DewPoint().Units() != unit.TemperatureCelsius {
+		t.Errorf("DewPoint: the dew point must be in the units of the temperature")
+	}
+
+	a, err = externalballistics.CreateAtmosphereWithDewPoint(altitude, pressure, temperature, temperature)
+	if err != nil {
+		t.Fatalf("CreateAtmosphereWithDewPoint: %v", err)
+	}
+	assertEqual(t, a.Humidity(), 1, 1e-9, "Humidity at dew point")
+
+	_, err = externalballistics.CreateAtmosphereWithDewPoint(altitude, pressure, temperature, unit.MustCreateTemperature(21, unit.TemperatureCelsius))
+	if !errors.Is(err, externalballistics.ErrInvalidHumidity) {
+		t.Errorf("CreateAtmosphereWithDewPoint: expected invalid humidity, got %v", err)
+	}
+
+	a, err = externalballistics.CreateAtmosphereWithWetBulb(altitude, pressure, temperature, unit.MustCreateTemperature(15, unit.TemperatureCelsius))
+	if err != nil {
+		t.Fatalf("CreateAtmosphereWithWetBulb: %v", err)
+	}
+	assertEqual(t, a.Humidity(), 0.59, 0.01, "Humidity from wet bulb")
+	a, _ = externalballistics.CreateAtmosphereWithWetBulb(altitude, pressure, temperature, temperature)
+	assertEqual(t, a.Humidity(), 1, 1e-9, "Humidity at wet bulb")
+	_, err = externalballistics.CreateAtmosphereWithWetBulb(altitude, pressure, temperature, unit.MustCreateTemperature(-10, unit.TemperatureCelsius))
+	if !errors.Is(err, externalballistics.ErrInvalidHumidity) {
+		t.Errorf("CreateAtmosphereWithWetBulb: expected invalid humidity, got %v", err)
+	}
+
+	a, err = externalballistics.CreateAtmosphereWithRelativeHumidity(altitude, pressure, temperature, 0.5)
+	if err != nil {
+		t.Fatalf("CreateAtmosphereWithRelativeHumidity: %v", err)
+	}
+	assertEqual(t, a.Humidity(), 0.5, 1e-9, "Humidity")
+	_, err = externalballistics.CreateAtmosphereWithRelativeHumidity(altitude, pressure, temperature, 50)
+	if !errors.Is(err, externalballistics.ErrInvalidHumidity) {
+		t.Errorf("CreateAtmosphereWithRelativeHumidity: expected invalid humidity, got %v", err)
+	}
+
+	a, _ = externalballistics.CreateAtmosphereWithRelativeHumidity(altitude, pressure, temperature, 0)
+	assertEqual(t, a.DewPoint().In(unit.TemperatureKelvin), 0, 1e-9, "Dew point of dry air")
+}
+
+func TestDensityModelCIPM2007(t *testing.T) {
+	const kilogramsPerCubicMeter = 16.01846337
+	var pressure = unit.MustCreatePressure(1013.25, unit.PressureHP)
+	var temperature = unit.MustCreateTemperature(20, unit.TemperatureCelsius)
+
+	var references = []struct {
+		temperature float64
+		humidity    float64
+		density     float64
+		polynomial  float64
+	}{
+		{20, 0, 1.2046, 0.005},
+		{20, 0.5, 1.1993, 0.005},
+		{0, 0, 1.2930, 0.005},
+		{30, 1, 1.1464, 0.02},
+	}
+	for _, reference := range references {
+		var a, err = externalballistics.CreateAtmosphereWithRelativeHumidity(unit.MustCreateDistance(0, unit.DistanceFoot), pressure,
+			unit.MustCreateTemperature(reference.temperature, unit.TemperatureCelsius), reference.humidity)
+		if err != nil {
+			t.Fatalf("CreateAtmosphereWithRelativeHumidity: %v", err)
+		}
+		if a.DensityModel() != externalballistics.DensityModelPolynomial {
+			t.Errorf("DensityModel: the polynomial model must be used by default")
+		}
+		var polynomial = a.Density() * kilogramsPerCubicMeter
+		if err = a.SetDensityModel(externalballistics.DensityModelCIPM2007); err != nil {
+			t.Fatalf("SetDensityModel: %v", err)
+		}
+		assertEqual(t, a.Density()*kilogramsPerCubicMeter, reference.density, 0.0005, fmt.Sprintf("CIPM-2007 density at %g°C %g%%", reference.temperature, reference.humidity*100))
+		assertEqual(t, polynomial, reference.density, reference.density*reference.polynomial, fmt.Sprintf("Polynomial density at %g°C %g%%", reference.temperature, reference.humidity*100))
+	}
+
+	var a, _ = externalballistics.CreateAtmosphere(unit.MustCreateDistance(0, unit.DistanceFoot), pressure, temperature, 0.5)
+	var cipm = a
+	cipm.SetDensityModel(externalballistics.DensityModelCIPM2007)
+	if a.DensityModel() != externalballistics.DensityModelPolynomial || cipm.DensityModel() != externalballistics.DensityModelCIPM2007 {
+		t.Errorf("SetDensityModel: the model must be set per atmosphere")
+	}
+	assertEqual(t, cipm.Mach().In(unit.VelocityFPS), a.Mach().In(unit.VelocityFPS), 1e-9, "Mach")
+
+	var calc = externalballistics.CreateTrajectoryCalculator()
+	var bc, _ = externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	var projectile = externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	var ammunition = externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	var weapon = externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard)))
+	var shotInfo = externalballistics.CreateShotParameters(calc.SightAngle(ammunition, weapon, cipm), unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(1000, unit.DistanceYard))
+	var data = calc.Trajectory(ammunition, weapon, cipm, shotInfo, nil)
+	var reference = calc.Trajectory(ammunition, weapon, a, externalballistics.CreateShotParameters(calc.SightAngle(ammunition, weapon, a), unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(1000, unit.DistanceYard)), nil)
+	assertEqual(t, data[1].Drop().In(unit.DistanceInch), reference[1].Drop().In(unit.DistanceInch), 2, "Drop with CIPM-2007 density")
+
+	if err := a.SetDensityModel(3); !errors.Is(err, externalballistics.ErrInvalidParameter) {
+		t.Errorf("SetDensityModel: expected invalid parameter, got %v", err)
+	}
+	if a.DensityModel() != externalballistics.DensityModelPolynomial {
+		t.Errorf("SetDensityModel: the model must not be changed by an invalid value")
+	}
+}
+
+func TestTrajectoryAllocations(t *testing.T) {
+	bc, _ := externalballistics.CreateBallisticCoefficient(0.223, externalballistics.DragTableG7)
+	projectile := externalballistics.CreateProjectile(bc, unit.MustCreateWeight(168, unit.WeightGrain))
+	ammo := externalballistics.CreateAmmunition(projectile, unit.MustCreateVelocity(2750, unit.VelocityFPS))
+	weapon := externalballistics.CreateWeapon(unit.MustCreateDistance(2, unit.DistanceInch), externalballistics.CreateZeroInfo(unit.MustCreateDistance(100, unit.DistanceYard)))
+	atmosphere := externalballistics.CreateDefaultAtmosphere()
+	calc := externalballistics.CreateTrajectoryCalculator()
+	shotInfo := externalballistics.CreateShotParameters(calc.SightAngle(ammo, weapon, atmosphere), unit.MustCreateDistance(1000, unit.DistanceYard), unit.MustCreateDistance(100, unit.DistanceYard))
+
+	//the trajectory is integrated with 1 ft step, so the allocations must not depend on the number of steps
+	var allocations = testing.AllocsPerRun(10, func() {
+		calc.Trajectory(ammo, weapon, atmosphere, shotInfo, nil)
+	})
+	if allocations > 20 {
+		t.Errorf("Trajectory: %g allocations per call", allocations)
+	}
+
+	//the point blank range calculation doesn't keep the rows of the trajectories it tries
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, _ = calc.CalculatePointBlankRange(ammo, weapon, atmosphere, unit.MustCreateDistance(6, unit.DistanceInch))
+	runtime.ReadMemStats(&after)
+	var bytes = after.TotalAlloc - before.TotalAlloc
+	if bytes > 256*1024 {
+		t.Errorf("CalculatePointBlankRange: %d bytes allocated per call", bytes)
+	}
+}